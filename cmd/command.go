@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/johnnyfreeman/bonsai/viewer"
+)
+
+// commandArgs returns the command given after "--", or nil when the
+// arguments are files
+func commandArgs() []string {
+	if flag.NArg() == 0 || os.Args[len(os.Args)-flag.NArg()-1] != "--" {
+		return nil
+	}
+	return flag.Args()
+}
+
+// openCommand runs a command and views its output. The reload key runs it
+// again. A command that cannot start or prints no usable JSON leaves an
+// empty viewer with the error in the status line rather than exiting.
+func openCommand(args []string, config viewer.Config, opts options) input {
+	load := func() (viewer.Model, error) {
+		return loadCommand(args, config, opts.format)
+	}
+	config.Reload = load
+
+	model, err := load()
+	if err != nil {
+		model = viewer.New(nil, config).
+			WithFilename(strings.Join(args, " "), 0).
+			WithStatus(err.Error(), true)
+	}
+	return input{model: model, name: args[0]}
+}
+
+// loadCommand runs a command and loads its stdout. A non-zero exit status
+// and anything written to stderr are shown in the status line.
+func loadCommand(args []string, config viewer.Config, format string) (viewer.Model, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return viewer.Model{}, runErr
+	}
+	status := commandStatus(runErr, stderr.Bytes())
+
+	data := stdout.Bytes()
+	model, err := loadModel(detectFormat(format, "", data), data, config, format == "auto")
+	if err != nil {
+		if status != "" {
+			return viewer.Model{}, fmt.Errorf("%s (output: %v)", status, err)
+		}
+		return viewer.Model{}, err
+	}
+
+	model = model.WithFilename(strings.Join(args, " "), int64(len(data)))
+	if status != "" {
+		model = model.WithStatus(status, runErr != nil)
+	}
+	return model, nil
+}
+
+// commandStatus summarises a command's exit status and stderr on one line
+func commandStatus(runErr error, stderr []byte) string {
+	var parts []string
+	if runErr != nil {
+		parts = append(parts, runErr.Error())
+	}
+	for _, line := range strings.Split(string(stderr), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
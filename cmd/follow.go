@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnnyfreeman/bonsai/viewer"
+)
+
+// followInterval is how often a followed file is checked for new data
+const followInterval = 250 * time.Millisecond
+
+// followFile polls filename for data appended after offset and sends each
+// batch of complete lines to the program, like tail -f. partial holds an
+// unterminated last line already read; it is completed by later writes.
+func followFile(send func(tea.Msg), filename string, offset int64, partial []byte) {
+	for range time.Tick(followInterval) {
+		info, err := os.Stat(filename)
+		if err != nil || info.Size() == offset {
+			continue
+		}
+		if info.Size() < offset {
+			// Truncated or replaced, e.g. by log rotation: start over
+			offset = 0
+			partial = nil
+		}
+
+		chunk, err := readFrom(filename, offset)
+		if err != nil {
+			continue
+		}
+		offset += int64(len(chunk))
+
+		data := append(partial, chunk...)
+		end := bytes.LastIndexByte(data, '\n')
+		if end < 0 {
+			partial = data
+			continue
+		}
+		partial = append([]byte(nil), data[end+1:]...)
+		send(viewer.LinesMsg{Lines: bytes.Split(data[:end], []byte{'\n'})})
+	}
+}
+
+// readFrom reads filename from offset to its current end
+func readFrom(filename string, offset int64) ([]byte, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
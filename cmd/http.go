@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnnyfreeman/bonsai/viewer"
+)
+
+// requestOptions describe the HTTP request made for a URL argument
+type requestOptions struct {
+	method  string
+	headers headerFlags
+	body    string
+	timeout time.Duration
+}
+
+// headerFlags collects repeated -H "Name: value" flags
+type headerFlags []string
+
+// String implements flag.Value
+func (h *headerFlags) String() string {
+	return strings.Join(*h, ", ")
+}
+
+// Set implements flag.Value
+func (h *headerFlags) Set(value string) error {
+	if !strings.Contains(value, ":") {
+		return fmt.Errorf("header %q is not in \"Name: value\" form", value)
+	}
+	*h = append(*h, value)
+	return nil
+}
+
+// isURL reports whether a command line argument is an HTTP(S) URL rather
+// than a file
+func isURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// httpSource fetches a URL for two tabs, the response body and the
+// response status, headers and timing. Refreshing either tab issues the
+// request again and updates the other tab with the same response.
+type httpSource struct {
+	url     string
+	req     requestOptions
+	configs [2]viewer.Config // body and response tabs
+	format  string
+
+	mu    sync.Mutex
+	sends [2]func(tea.Msg)
+}
+
+// response is the outcome of one request
+type response struct {
+	status     string
+	statusCode int
+	proto      string
+	header     http.Header
+	body       []byte
+	firstByte  time.Duration
+	total      time.Duration
+}
+
+// openURL requests a URL and returns the body and response tabs. A failed
+// request or a body that is not JSON is reported in the status line rather
+// than exiting, so that it can be retried with the reload key.
+func openURL(url string, req requestOptions, config viewer.Config, opts options) []input {
+	if req.method == "" {
+		req.method = http.MethodGet
+		if req.body != "" {
+			req.method = http.MethodPost
+		}
+	}
+
+	src := &httpSource{url: url, req: req, format: opts.format}
+	for i := range src.configs {
+		tab := i
+		src.configs[i] = config
+		src.configs[i].Reload = func() (viewer.Model, error) {
+			return src.reload(tab)
+		}
+	}
+
+	body, info, err := src.load()
+	if err != nil {
+		body = viewer.New(nil, src.configs[0]).WithFilename(url, 0).WithStatus(err.Error(), true)
+		info = viewer.New(nil, src.configs[1]).WithFilename(url, 0).WithStatus(err.Error(), true)
+	}
+	inputs := []input{
+		{model: body, name: "body"},
+		{model: info, name: "response"},
+	}
+	for i := range inputs {
+		tab := i
+		inputs[i].watch = func(send func(tea.Msg)) {
+			src.mu.Lock()
+			src.sends[tab] = send
+			src.mu.Unlock()
+		}
+	}
+	return inputs
+}
+
+// reload requests the URL again for one tab, sending the other tab its
+// part of the new response. A failed request is returned as an error to
+// both tabs, so that they keep showing the previous response.
+func (s *httpSource) reload(tab int) (viewer.Model, error) {
+	var models [2]viewer.Model
+	var err error
+	models[0], models[1], err = s.load()
+
+	s.mu.Lock()
+	send := s.sends[1-tab]
+	s.mu.Unlock()
+	if send != nil {
+		send(viewer.ReloadMsg{Model: models[1-tab], Err: err})
+	}
+	return models[tab], err
+}
+
+// load requests the URL and builds the body and response viewers. An
+// error means no response was received.
+func (s *httpSource) load() (body, info viewer.Model, err error) {
+	resp, err := s.fetch()
+	if err != nil {
+		return body, info, err
+	}
+
+	summary := fmt.Sprintf("%s %s: %s in %s", s.req.method, s.url, resp.status, resp.total.Round(time.Millisecond))
+	failed := resp.statusCode >= 400
+
+	body, err = loadModel(detectFormat(s.format, s.url, resp.body), resp.body, s.configs[0], s.format == "auto")
+	if err != nil {
+		body = viewer.New(nil, s.configs[0])
+		summary += fmt.Sprintf(" (body is not JSON: %v)", err)
+		failed = true
+	}
+	body = body.WithFilename(s.url, int64(len(resp.body))).WithStatus(summary, failed)
+
+	info = viewer.New(resp.describe(s.req.method, s.url), s.configs[1]).
+		WithFilename(s.url, int64(len(resp.body))).
+		WithStatus(summary, failed)
+	return body, info, nil
+}
+
+// fetch issues the request, timing it
+func (s *httpSource) fetch() (*response, error) {
+	var body io.Reader
+	if s.req.body != "" {
+		data := []byte(s.req.body)
+		if strings.HasPrefix(s.req.body, "@") {
+			var err error
+			if data, err = os.ReadFile(s.req.body[1:]); err != nil {
+				return nil, err
+			}
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(s.req.method, s.url, body)
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range s.req.headers {
+		name, value, _ := strings.Cut(header, ":")
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	resp := &response{}
+	start := time.Now()
+	trace := &httptrace.ClientTrace{
+		GotFirstResponseByte: func() { resp.firstByte = time.Since(start) },
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	client := &http.Client{Timeout: s.req.timeout}
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	resp.body, err = io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.total = time.Since(start)
+	resp.status = r.Status
+	resp.statusCode = r.StatusCode
+	resp.proto = r.Proto
+	resp.header = r.Header
+	return resp, nil
+}
+
+// describe returns the response status, headers and timing as JSON-like
+// data for the response tab
+func (r *response) describe(method, url string) map[string]interface{} {
+	headers := make(map[string]interface{}, len(r.header))
+	for name, values := range r.header {
+		if len(values) == 1 {
+			headers[name] = values[0]
+			continue
+		}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			list[i] = v
+		}
+		headers[name] = list
+	}
+
+	return map[string]interface{}{
+		"request": map[string]interface{}{
+			"method": method,
+			"url":    url,
+		},
+		"status":     r.status,
+		"statusCode": float64(r.statusCode),
+		"proto":      r.proto,
+		"headers":    headers,
+		"timing": map[string]interface{}{
+			"firstByte": r.firstByte.Round(time.Millisecond).String(),
+			"total":     r.total.Round(time.Millisecond).String(),
+		},
+		"size": float64(len(r.body)),
+	}
+}
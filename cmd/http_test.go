@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnnyfreeman/bonsai/viewer"
+)
+
+// recordingServer serves a JSON count of the requests it has handled and
+// records the last one
+type recordingServer struct {
+	mu     sync.Mutex
+	count  int
+	method string
+	header http.Header
+	body   string
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.count++
+	s.method, s.header, s.body = r.Method, r.Header, string(body)
+	count := s.count
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Served-By", "test")
+	json.NewEncoder(w).Encode(map[string]int{"count": count})
+}
+
+func TestOpenURLSendsRequestOptions(t *testing.T) {
+	rec := &recordingServer{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	req := requestOptions{method: http.MethodPut, body: `{"a":1}`, timeout: 5 * time.Second}
+	req.headers.Set("Authorization: Bearer token")
+	req.headers.Set("X-Extra:  spaced ")
+	inputs := openURL(srv.URL+"/items", req, viewer.DefaultConfig(), options{format: "auto"})
+
+	if rec.method != http.MethodPut || rec.body != `{"a":1}` {
+		t.Errorf("server got %s %q, want PUT with the body", rec.method, rec.body)
+	}
+	if got := rec.header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization header = %q", got)
+	}
+	if got := rec.header.Get("X-Extra"); got != "spaced" {
+		t.Errorf("X-Extra header = %q, want it trimmed", got)
+	}
+
+	if len(inputs) != 2 || inputs[0].name != "body" || inputs[1].name != "response" {
+		t.Fatalf("tabs = %v, want body and response", inputs)
+	}
+	body := inputs[0].model.GetFilteredData().(map[string]interface{})
+	if body["count"] != json.Number("1") {
+		t.Errorf("body = %v, want count 1", body)
+	}
+
+	info := inputs[1].model.GetFilteredData().(map[string]interface{})
+	if info["status"] != "200 OK" || info["statusCode"] != float64(200) {
+		t.Errorf("status = %v %v, want 200 OK", info["status"], info["statusCode"])
+	}
+	if got := info["headers"].(map[string]interface{})["X-Served-By"]; got != "test" {
+		t.Errorf("X-Served-By = %v", got)
+	}
+	request := info["request"].(map[string]interface{})
+	if request["method"] != http.MethodPut || request["url"] != srv.URL+"/items" {
+		t.Errorf("request = %v", request)
+	}
+	timing := info["timing"].(map[string]interface{})
+	for _, name := range []string{"firstByte", "total"} {
+		if _, err := time.ParseDuration(timing[name].(string)); err != nil {
+			t.Errorf("timing %s = %v: %v", name, timing[name], err)
+		}
+	}
+}
+
+func TestOpenURLPostsBodyFromFile(t *testing.T) {
+	rec := &recordingServer{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(path, []byte(`{"from":"file"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	openURL(srv.URL, requestOptions{body: "@" + path}, viewer.DefaultConfig(), options{format: "auto"})
+
+	if rec.method != http.MethodPost || rec.body != `{"from":"file"}` {
+		t.Errorf("server got %s %q, want POST of the file", rec.method, rec.body)
+	}
+}
+
+func TestOpenURLKeepsDataWhenRefreshFails(t *testing.T) {
+	srv := httptest.NewServer(&recordingServer{})
+	inputs := openURL(srv.URL, requestOptions{timeout: 5 * time.Second}, viewer.DefaultConfig(), options{format: "auto"})
+
+	var sent []tea.Msg
+	inputs[1].watch(func(msg tea.Msg) { sent = append(sent, msg) })
+
+	body, _ := inputs[0].model.Reload()
+	if got := body.GetFilteredData().(map[string]interface{})["count"]; got != json.Number("2") {
+		t.Errorf("count after refresh = %v, want 2", got)
+	}
+	if len(sent) != 1 || sent[0].(viewer.ReloadMsg).Err != nil {
+		t.Fatalf("response tab got %v, want the new response", sent)
+	}
+
+	srv.Close()
+	body, _ = body.Reload()
+	if got := body.GetFilteredData().(map[string]interface{})["count"]; got != json.Number("2") {
+		t.Errorf("count after failed refresh = %v, want the previous 2", got)
+	}
+	if view := body.View(); !strings.Contains(view, "Reload failed") {
+		t.Errorf("view does not report the failed refresh:\n%s", view)
+	}
+	if len(sent) != 2 || sent[1].(viewer.ReloadMsg).Err == nil {
+		t.Errorf("response tab got %v, want the refresh error", sent[1:])
+	}
+}
@@ -1,100 +1,314 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/johnnyfreeman/bonsai/viewer"
 )
 
 func main() {
-	var data []byte
-	var filename string
-	var fileSize int64
-	var err error
+	format := flag.String("format", "auto", "input format: auto, "+strings.Join(viewer.FormatNames(), ", ")+" (json5 also as jsonc)")
+	lenient := flag.Bool("lenient", false, "show the part of a malformed document that parsed")
+	follow := flag.Bool("f", false, "follow a JSON Lines file, showing records as they are appended")
+	watch := flag.Bool("w", false, "watch the file and reload it when it changes")
+	infer := flag.Bool("infer", true, "read numbers, booleans and empty fields in CSV/TSV as such rather than as strings")
+	embedded := flag.Bool("embedded", true, "show strings holding JSON objects or arrays as expandable values")
+	decodeBase64 := flag.Bool("base64", false, "show JWTs and base64 strings with their decoded contents")
+	timestamps := flag.Bool("timestamps", false, "annotate epoch numbers and RFC 3339 strings with their date and age")
+	timeZone := flag.String("tz", "Local", "time zone for timestamp annotations, such as UTC or Europe/Berlin")
+	req := requestOptions{}
+	flag.StringVar(&req.method, "X", "", "HTTP method for URL arguments (default GET, or POST with -d)")
+	flag.Var(&req.headers, "H", "HTTP request header \"Name: value\" for URL arguments (repeatable)")
+	flag.StringVar(&req.body, "d", "", "HTTP request body for URL arguments, or @file to read it from a file")
+	flag.DurationVar(&req.timeout, "timeout", 30*time.Second, "HTTP request timeout")
+	flag.Usage = usage
+	flag.Parse()
 
 	// Check if we have stdin data or a file argument
 	stat, _ := os.Stdin.Stat()
 	hasStdin := (stat.Mode() & os.ModeCharDevice) == 0
 
-	if hasStdin {
+	if *follow && *watch {
+		log.Fatal("-f and -w cannot be combined")
+	}
+	if (*follow || *watch) && (hasStdin || flag.NArg() < 1 || commandArgs() != nil) {
+		log.Fatal("Follow and watch modes need a file argument")
+	}
+
+	// Create the viewer model with CLI-specific configuration
+	config := viewer.DefaultConfig().
+		WithTheme(viewer.TokyoNightTheme())
+	config.MarkInexactNumbers = true
+	config.Lenient = *lenient
+	config.InferTypes = *infer
+	config.ExpandEmbeddedJSON = *embedded
+	config.DecodeBase64 = *decodeBase64
+	config.ShowTimestamps = *timestamps
+	loc, err := time.LoadLocation(*timeZone)
+	if err != nil {
+		log.Fatalf("Invalid time zone: %v", err)
+	}
+	config.TimeZone = loc
+
+	// Set up error handling
+	config.OnError = func(err error) {
+		log.Printf("Bonsai Error: %v", err)
+	}
+
+	opts := options{format: *format, follow: *follow, watch: *watch}
+	var inputs []input
+	if args := commandArgs(); args != nil {
+		inputs = append(inputs, openCommand(args, config, opts))
+	} else if hasStdin {
 		// Read from stdin
-		data, err = io.ReadAll(os.Stdin)
+		data, err := io.ReadAll(os.Stdin)
 		if err != nil {
 			log.Fatalf("Error reading from stdin: %v", err)
 		}
-		filename = "<stdin>"
-		fileSize = int64(len(data))
+		inputs = append(inputs, openInput("<stdin>", data, config, opts))
 	} else {
-		// Check for file argument
-		if len(os.Args) < 2 {
-			fmt.Println("Usage: bonsai <file.json>")
-			fmt.Println("   or: cat file.json | bonsai")
-			fmt.Println("   or: curl -s api.example.com/data.json | bonsai")
-			fmt.Println("\nBonsai - A terminal-based JSON viewer with vim-like navigation.")
-			fmt.Println("\nFeatures:")
-			fmt.Println("  • hjkl navigation")
-			fmt.Println("  • Expand/collapse nodes")
-			fmt.Println("  • Text and JSONPath filtering")
-			fmt.Println("  • Search functionality")
-			fmt.Println("  • Copy to clipboard")
-			fmt.Println("  • Multiple themes")
-			fmt.Println("\nPress ? for help when running")
+		// Check for file arguments
+		if flag.NArg() < 1 {
+			usage()
 			os.Exit(1)
 		}
 
-		filename = os.Args[1]
+		for _, filename := range flag.Args() {
+			if isURL(filename) {
+				if *follow || *watch {
+					log.Fatal("Follow and watch modes need file arguments, not URLs")
+				}
+				inputs = append(inputs, openURL(filename, req, config, opts)...)
+				continue
+			}
 
-		// Read the file
-		file, err := os.Open(filename)
-		if err != nil {
-			log.Fatalf("Error opening file: %v", err)
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				log.Fatalf("Error reading file: %v", err)
+			}
+			inputs = append(inputs, openInput(filename, data, config, opts))
 		}
-		defer file.Close()
+	}
 
-		// Get file info for display
-		fileInfo, err := file.Stat()
-		if err != nil {
-			log.Fatalf("Error getting file info: %v", err)
+	// Several files are shown as tabs
+	var model tea.Model = inputs[0].model
+	if len(inputs) > 1 {
+		models := make([]viewer.Model, len(inputs))
+		names := make([]string, len(inputs))
+		for i, in := range inputs {
+			models[i] = in.model
+			names[i] = in.name
 		}
-		fileSize = fileInfo.Size()
+		model = newTabs(models, names)
+	}
 
-		// Read file content
-		data, err = io.ReadAll(file)
+	// Run the program
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	for i, in := range inputs {
+		if in.watch == nil {
+			continue
+		}
+		send := p.Send
+		if len(inputs) > 1 {
+			index := i
+			send = func(msg tea.Msg) { p.Send(tabMsg{index: index, msg: msg}) }
+		}
+		go in.watch(send)
+	}
+	if _, err := p.Run(); err != nil {
+		log.Fatalf("Error running program: %v", err)
+	}
+}
+
+// options are the command line flags that shape how inputs are loaded
+type options struct {
+	format string
+	follow bool
+	watch  bool
+}
+
+// input is a loaded viewer, its tab name and, in follow or watch mode, the
+// function that keeps it up to date by sending it messages
+type input struct {
+	model viewer.Model
+	name  string
+	watch func(send func(tea.Msg))
+}
+
+// openInput loads one file, or stdin, exiting with a report when it fails
+// to parse. gzip and bzip2 input is decompressed first.
+func openInput(filename string, raw []byte, config viewer.Config, opts options) input {
+	name := filepath.Base(filename)
+	data, compression, err := decompress(raw)
+	if err != nil {
+		log.Fatalf("Error decompressing %s: %v", filename, err)
+	}
+	if compression != "" && opts.follow {
+		log.Fatalf("Cannot follow %s compressed file %s", compression, filename)
+	}
+
+	// In follow mode an unterminated last line may still be being written,
+	// so it is held back until the follower sees the rest of it
+	format := opts.format
+	var partial []byte
+	if opts.follow {
+		format = "jsonl"
+		end := bytes.LastIndexByte(data, '\n') + 1
+		raw, partial = raw[:end], raw[end:]
+		data = raw
+	}
+
+	resolved := detectFormat(format, strings.TrimSuffix(strings.TrimSuffix(filename, ".gz"), ".bz2"), data)
+	build := func(raw, data []byte, compression string) (viewer.Model, error) {
+		model, err := loadModel(resolved, data, config, format == "auto")
 		if err != nil {
-			log.Fatalf("Error reading file: %v", err)
+			return model, err
+		}
+		model = model.WithFilename(name, int64(len(data)))
+		if compression != "" {
+			model = model.WithCompression(compression, int64(len(raw)))
 		}
+		return model, nil
+	}
+	load := func(raw []byte) (viewer.Model, error) {
+		data, compression, err := decompress(raw)
+		if err != nil {
+			return viewer.Model{}, err
+		}
+		return build(raw, data, compression)
 	}
 
-	// Create the viewer model with CLI-specific configuration
-	config := viewer.DefaultConfig().
-		WithTheme(viewer.TokyoNightTheme())
+	if filename != "<stdin>" && !opts.follow {
+		config.Reload = func() (viewer.Model, error) {
+			return loadFile(filename, load)
+		}
+	}
+	model, err := build(raw, data, compression)
+	if err != nil {
+		reportParseError(filename, err)
+		os.Exit(1)
+	}
 
-	// Set up error handling
-	config.OnError = func(err error) {
-		log.Printf("Bonsai Error: %v", err)
+	in := input{model: model, name: name}
+	switch {
+	case opts.follow:
+		in.watch = func(send func(tea.Msg)) {
+			followFile(send, filename, int64(len(raw)+len(partial)), partial)
+		}
+	case opts.watch:
+		in.watch = func(send func(tea.Msg)) {
+			watchFile(send, filename, load)
+		}
+	}
+	return in
+}
+
+// decompress returns data decompressed when it is gzip or bzip2, and the
+// name of the compression format found
+func decompress(data []byte) ([]byte, string, error) {
+	reader, compression, err := viewer.Decompress(bytes.NewReader(data))
+	if err != nil || compression == "" {
+		return data, compression, err
+	}
+	data, err = io.ReadAll(reader)
+	return data, compression, err
+}
+
+func usage() {
+	fmt.Println("Usage: bonsai [flags] <file.json>...")
+	fmt.Println("   or: bonsai -f app.log.jsonl")
+	fmt.Println("   or: bonsai -w fixture.json")
+	fmt.Println("   or: bonsai -- kubectl get pods -o json")
+	fmt.Println("   or: bonsai -X POST -H 'Content-Type: application/json' -d '{}' https://api.example.com/items")
+	fmt.Println("   or: cat file.json | bonsai")
+	fmt.Println("   or: curl -s api.example.com/data.json | bonsai")
+	fmt.Println("\nBonsai - A terminal-based JSON viewer with vim-like navigation.")
+	fmt.Println("\nFlags:")
+	flag.CommandLine.SetOutput(os.Stdout)
+	flag.PrintDefaults()
+	fmt.Println("\nFeatures:")
+	fmt.Println("  • hjkl navigation")
+	fmt.Println("  • Expand/collapse nodes")
+	fmt.Println("  • Text and JSONPath filtering")
+	fmt.Println("  • Search functionality")
+	fmt.Println("  • Copy to clipboard")
+	fmt.Println("  • Multiple themes")
+	fmt.Println("  • JSON Lines (NDJSON), JSON5/JSONC, YAML, TOML, CSV/TSV, XML, MessagePack, CBOR and multi-document input")
+	fmt.Println("  • Follow growing JSON Lines files (-f)")
+	fmt.Println("  • Reload files when they change (-w)")
+	fmt.Println("  • Several files open as tabs")
+	fmt.Println("  • View a command's output and re-run it with R")
+	fmt.Println("  • Fetch HTTP(S) URLs, with status, headers and timing in a second tab")
+	fmt.Println("  • gzip and bzip2 input is decompressed automatically")
+	fmt.Println("  • JSON embedded in strings expands inline")
+	fmt.Println("  • JWTs and base64 strings are decoded with -base64")
+	fmt.Println("  • Timestamps show their date and age with -timestamps; press o to sort by time")
+	fmt.Println("\nPress ? for help when running")
+}
+
+// detectFormat resolves the "auto" format from the file extension, falling
+// back to sniffing the content
+func detectFormat(format, filename string, data []byte) string {
+	if format != "auto" {
+		return format
+	}
+	return viewer.DetectFormat(filename, data)
+}
+
+// loadModel creates a viewer for data in a resolved format
+func loadModel(format string, data []byte, config viewer.Config, auto bool) (viewer.Model, error) {
+	if format == "json" {
+		return loadJSON(data, config, auto)
 	}
+	return viewer.NewFromFormat(format, data, config)
+}
 
-	// Create the model
+// loadJSON parses data as a single JSON document. When the format was not
+// given explicitly it falls back to a stream of concatenated documents, or
+// to JSON5 for config files that carry comments or trailing commas.
+func loadJSON(data []byte, config viewer.Config, auto bool) (viewer.Model, error) {
 	model, err := viewer.NewFromJSON(data, config)
-	if err != nil {
-		log.Fatalf("Error parsing JSON: %v", err)
+	if !auto {
+		return model, err
 	}
 
-	// Add file information
-	if filename == "<stdin>" {
-		model = model.WithFilename(filename, fileSize)
-	} else {
-		model = model.WithFilename(filepath.Base(filename), fileSize)
+	if errors.Is(err, viewer.ErrTrailingData) || errors.Is(model.LoadError(), viewer.ErrTrailingData) {
+		config.MultiDocument = true
+		return viewer.NewFromReader(bytes.NewReader(data), config)
 	}
+	if err != nil || model.LoadError() != nil {
+		relaxed, relaxedErr := viewer.NewFromJSON5(data, config)
+		if relaxedErr == nil && relaxed.LoadError() == nil {
+			return relaxed, nil
+		}
+	}
+	return model, err
+}
 
-	// Run the program
-	p := tea.NewProgram(model, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		log.Fatalf("Error running program: %v", err)
+// reportParseError prints a parse error with the offending lines marked and,
+// where one applies, a hint at the likely mistake
+func reportParseError(filename string, err error) {
+	var parseErr *viewer.ParseError
+	if !errors.As(err, &parseErr) {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", filename, err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Error parsing %s at %s: %v\n\n",
+		filename, parseErr.Position(), parseErr.Err)
+	fmt.Fprintln(os.Stderr, parseErr.Snippet(2))
+	if hint := parseErr.Hint(); hint != "" {
+		fmt.Fprintf(os.Stderr, "\nHint: %s\n", hint)
 	}
+	fmt.Fprintln(os.Stderr, "\nRun with -lenient to browse the part that parsed.")
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnnyfreeman/bonsai/viewer"
+)
+
+// tabs shows one viewer per file, each with its own cursor, filter and
+// expansion state, switching between them with tab, shift+tab or 1-9
+type tabs struct {
+	models []viewer.Model
+	active int
+}
+
+// tabMsg routes a message to the viewer of one tab, so that reloads and
+// timers reach the tab that asked for them whichever tab is shown
+type tabMsg struct {
+	index int
+	msg   tea.Msg
+}
+
+// newTabs creates the tab set, labelling each viewer's tab bar
+func newTabs(models []viewer.Model, names []string) tabs {
+	for i := range models {
+		models[i] = models[i].WithTabs(names, i)
+	}
+	return tabs{models: models}
+}
+
+// Init implements tea.Model
+func (t tabs) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for i, model := range t.models {
+		cmds = append(cmds, tabCmd(i, model.Init()))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update implements tea.Model
+func (t tabs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		var cmds []tea.Cmd
+		for i := range t.models {
+			cmds = append(cmds, t.update(i, msg))
+		}
+		return t, tea.Batch(cmds...)
+
+	case tabMsg:
+		return t, t.update(msg.index, msg.msg)
+
+	case tea.KeyMsg:
+		if t.models[t.active].IsInputActive() {
+			break
+		}
+		switch key := msg.String(); key {
+		case "tab":
+			t.active = (t.active + 1) % len(t.models)
+			return t, nil
+		case "shift+tab":
+			t.active = (t.active + len(t.models) - 1) % len(t.models)
+			return t, nil
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if i := int(key[0] - '1'); i < len(t.models) {
+				t.active = i
+			}
+			return t, nil
+		}
+	}
+
+	return t, t.update(t.active, msg)
+}
+
+// update passes a message to the viewer of tab i
+func (t *tabs) update(i int, msg tea.Msg) tea.Cmd {
+	model, cmd := t.models[i].Update(msg)
+	t.models[i] = model.(viewer.Model)
+	return tabCmd(i, cmd)
+}
+
+// View implements tea.Model
+func (t tabs) View() string {
+	return t.models[t.active].View()
+}
+
+// tabCmd tags the message a viewer's command produces with its tab
+func tabCmd(i int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.QuitMsg:
+			return msg
+		default:
+			return tabMsg{index: i, msg: msg}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnnyfreeman/bonsai/viewer"
+)
+
+// watchInterval is how often a watched file is checked for changes
+const watchInterval = 500 * time.Millisecond
+
+// watchFile polls filename and, whenever its size or modification time
+// changes, loads it again and sends the result to the program
+func watchFile(send func(tea.Msg), filename string, load func([]byte) (viewer.Model, error)) {
+	info, _ := os.Stat(filename)
+	for range time.Tick(watchInterval) {
+		current, err := os.Stat(filename)
+		if err != nil || (info != nil && current.Size() == info.Size() && current.ModTime().Equal(info.ModTime())) {
+			continue
+		}
+		info = current
+
+		model, err := loadFile(filename, load)
+		send(viewer.ReloadMsg{Model: model, Err: err})
+	}
+}
+
+// loadFile reads filename and loads it for a reload
+func loadFile(filename string, load func([]byte) (viewer.Model, error)) (viewer.Model, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return viewer.Model{}, err
+	}
+	return load(data)
+}
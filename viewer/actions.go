@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/PaesslerAG/jsonpath"
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -102,6 +103,21 @@ func (m *Model) toggleExpansion() {
 	}
 }
 
+// revealNode expands the ancestors of node and moves the cursor onto it
+func (m *Model) revealNode(node *Node) {
+	for _, parent := range node.GetParentChain() {
+		parent.Expanded = true
+	}
+	m.updateViewNodes()
+	for i, n := range m.viewNodes {
+		if n == node {
+			m.cursor = i
+			break
+		}
+	}
+	m.updateViewport()
+}
+
 // Tree operations
 func (m *Model) expandAll() {
 	m.root.ExpandAll()
@@ -117,6 +133,7 @@ func (m *Model) collapseAll() {
 
 func (m *Model) resetView() {
 	m.filter = ""
+	m.jsonpathQuery = false
 	m.filterMode = false
 	m.jsonpathMode = false
 	m.searchMode = false
@@ -125,7 +142,7 @@ func (m *Model) resetView() {
 	m.searchIndex = 0
 	m.cursor = 0
 
-	m.root = BuildTree(m.rawData, "", "$")
+	m.root = m.source.Clone()
 	if m.config.InitiallyExpanded {
 		m.root.Expanded = true
 	}
@@ -133,10 +150,191 @@ func (m *Model) resetView() {
 	m.updateViewport()
 }
 
+// toggleBytesFormat switches byte strings between hex and base64
+func (m *Model) toggleBytesFormat() {
+	m.bytesBase64 = !m.bytesBase64
+	m.updateViewport()
+}
+
+// sortByTime orders the list holding the node under the cursor by the
+// time at the cursor: the elements themselves, or the field at the same
+// place in each of them. Elements without a time go last, and sorting a
+// list again reverses it. The order lasts until the view is rebuilt.
+func (m *Model) sortByTime() {
+	if m.cursor >= len(m.viewNodes) {
+		return
+	}
+	node := m.viewNodes[m.cursor]
+	if _, ok := nodeTime(node); !ok {
+		m.setStatus("Not a time to sort by", true)
+		return
+	}
+
+	// Find the enclosing array and the keys from its element to the node
+	var keys []string
+	elem := node
+	for elem.Parent != nil && elem.Parent.Type != ArrayNode {
+		keys = append([]string{elem.Key}, keys...)
+		elem = elem.Parent
+	}
+	list := elem.Parent
+	if list == nil {
+		m.setStatus("Not in a list to sort", true)
+		return
+	}
+
+	type entry struct {
+		node *Node
+		time time.Time
+		ok   bool
+	}
+	entries := make([]entry, len(list.Children))
+	for i, child := range list.Children {
+		entries[i].node = child
+		if field := descendant(child, keys); field != nil {
+			entries[i].time, entries[i].ok = nodeTime(field)
+		}
+	}
+	before := func(a, b entry) bool {
+		return a.ok && (!b.ok || a.time.Before(b.time))
+	}
+	order := "oldest"
+	if sort.SliceIsSorted(entries, func(i, j int) bool { return before(entries[i], entries[j]) }) {
+		before = func(a, b entry) bool {
+			return a.ok && (!b.ok || a.time.After(b.time))
+		}
+		order = "newest"
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return before(entries[i], entries[j]) })
+	for i, e := range entries {
+		list.Children[i] = e.node
+	}
+
+	m.updateViewNodes()
+	for i, n := range m.viewNodes {
+		if n == node {
+			m.cursor = i
+			break
+		}
+	}
+	m.updateViewport()
+	by := "time"
+	if len(keys) > 0 {
+		by = strings.Join(keys, ".")
+	}
+	m.setStatus(fmt.Sprintf("Sorted %s by %s, %s first", list.Path, by, order), false)
+}
+
+// descendant follows keys down from n, returning nil where one is missing
+func descendant(n *Node, keys []string) *Node {
+	for _, k := range keys {
+		var next *Node
+		for _, child := range n.Children {
+			if child.Key == k {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// viewState is the part of the view that survives rebuilding the tree
+type viewState struct {
+	expanded   map[string]bool
+	cursorPath string
+	matchPaths []string
+}
+
+// saveViewState records expansion, cursor and search matches by path
+func (m *Model) saveViewState() viewState {
+	state := viewState{expanded: make(map[string]bool)}
+	var walk func(*Node)
+	walk = func(n *Node) {
+		if n.Expanded {
+			state.expanded[n.Path] = true
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(m.root)
+
+	if node := m.GetCurrentNode(); node != nil {
+		state.cursorPath = node.Path
+	}
+	for _, match := range m.searchMatches {
+		state.matchPaths = append(state.matchPaths, match.Path)
+	}
+	return state
+}
+
+// restoreViewState applies a saved view state to the current tree. Paths
+// that no longer exist are dropped and the cursor stays in range.
+func (m *Model) restoreViewState(state viewState) {
+	byPath := make(map[string]*Node)
+	var walk func(*Node)
+	walk = func(n *Node) {
+		n.Expanded = state.expanded[n.Path]
+		byPath[n.Path] = n
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(m.root)
+
+	m.searchMatches = nil
+	for _, path := range state.matchPaths {
+		if node := byPath[path]; node != nil {
+			m.searchMatches = append(m.searchMatches, node)
+		}
+	}
+	if m.searchIndex >= len(m.searchMatches) {
+		m.searchIndex = 0
+	}
+
+	m.updateViewNodes()
+	for i, node := range m.viewNodes {
+		if node.Path == state.cursorPath {
+			m.cursor = i
+			break
+		}
+	}
+	m.updateViewport()
+}
+
+// rebuildTree rebuilds the displayed tree from the source, re-applying an
+// active JSONPath query and keeping the view state
+func (m *Model) rebuildTree() {
+	state := m.saveViewState()
+	m.root = m.source.Clone()
+	if m.isJSONPathFilter() && m.filter != "" {
+		if result, err := queryJSONPath(m.filter, m.rawData); err == nil {
+			m.root = m.index.build(result, "", "$")
+		}
+	}
+	m.insertRemoved()
+	m.restoreViewState(state)
+}
+
+// isJSONPathFilter reports whether the filter holds a JSONPath query, being
+// typed or applied, rather than plain text
+func (m *Model) isJSONPathFilter() bool {
+	if m.jsonpathMode {
+		return true
+	}
+	return !m.filterMode && !m.searchMode && !m.gotoMode && m.jsonpathQuery
+}
+
 // Input mode actions
 func (m *Model) enterFilterMode() {
 	m.filterMode = true
 	m.filter = ""
+	m.jsonpathQuery = false
 }
 
 func (m *Model) enterJSONPathMode() {
@@ -181,11 +379,13 @@ func (m *Model) pathToWildcard(path string) string {
 func (m *Model) enterSearchMode() {
 	m.searchMode = true
 	m.filter = ""
+	m.jsonpathQuery = false
 }
 
 func (m *Model) enterGotoMode() {
 	m.gotoMode = true
 	m.filter = ""
+	m.jsonpathQuery = false
 }
 
 // Input handling
@@ -235,6 +435,7 @@ func (m *Model) applyInput() {
 	m.jsonpathMode = false
 	m.searchMode = false
 	m.gotoMode = false
+	m.jsonpathQuery = wasJSONPathMode
 	
 	if m.filter != "" {
 		m.config.OnFilter(m.filter)
@@ -255,11 +456,12 @@ func (m *Model) cancelInput() {
 	m.searchMode = false
 	m.gotoMode = false
 	m.filter = ""
+	m.jsonpathQuery = false
 	
 	// If we were in JSONPath mode, restore the original position
 	if wasJSONPathMode && m.savedNodePath != "" {
 		// Restore original view state
-		m.root = BuildTree(m.rawData, "", "$")
+		m.root = m.source.Clone()
 		
 		// Expand all nodes to ensure the saved path is visible
 		m.root.ExpandAll()
@@ -316,7 +518,7 @@ func (m *Model) applyLiveJSONPathFilter() {
 	// Don't apply empty filters
 	if m.filter == "" {
 		// Reset to original data when filter is empty
-		m.root = BuildTree(m.rawData, "", "$")
+		m.root = m.source.Clone()
 		if m.config.InitiallyExpanded {
 			m.root.Expanded = true
 		}
@@ -329,9 +531,9 @@ func (m *Model) applyLiveJSONPathFilter() {
 	// Try to apply JSONPath filter, but don't show errors during live typing
 	// Only apply if it's a potentially valid JSONPath (starts with $ or has some basic structure)
 	if strings.HasPrefix(m.filter, "$") || strings.Contains(m.filter, ".") {
-		result, err := jsonpath.Get(m.filter, m.rawData)
+		result, err := queryJSONPath(m.filter, m.rawData)
 		if err == nil {
-			m.root = BuildTree(result, "", "$")
+			m.root = m.index.build(result, "", "$")
 			m.root.Expanded = true
 			
 			// Reset cursor to top since structure changed significantly
@@ -350,13 +552,13 @@ func (m *Model) applyJSONPathFilter() {
 		return
 	}
 
-	result, err := jsonpath.Get(m.filter, m.rawData)
+	result, err := queryJSONPath(m.filter, m.rawData)
 	if err != nil {
 		m.config.OnError(err)
 		return
 	}
 
-	m.root = BuildTree(result, "", "$")
+	m.root = m.index.build(result, "", "$")
 	m.root.Expanded = true
 }
 
@@ -439,10 +641,21 @@ func (m *Model) copyValue() {
 	if m.cursor < len(m.viewNodes) {
 		node := m.viewNodes[m.cursor]
 		var value string
-		switch node.Type {
-		case ObjectNode, ArrayNode:
-			jsonBytes, _ := json.MarshalIndent(node.Value, "", "  ")
+		switch {
+		case node.Raw != "":
+			value = node.Raw
+		case node.Type == ObjectNode, node.Type == ArrayNode:
+			jsonBytes, err := json.MarshalIndent(orderedValue(node), "", "  ")
+			if err != nil {
+				m.setStatus(fmt.Sprintf("Copy failed: %v", err), true)
+				return
+			}
 			value = string(jsonBytes)
+		case node.Type == BytesNode:
+			value = node.Value.(Bytes).String()
+			if m.bytesBase64 {
+				value = node.Value.(Bytes).Base64()
+			}
 		default:
 			value = fmt.Sprintf("%v", node.Value)
 		}
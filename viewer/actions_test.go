@@ -0,0 +1,54 @@
+package viewer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterKindFollowsHowItWasApplied(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.InitiallyExpanded = true
+	m, err := NewFromJSON([]byte(`{"items": [{"name": "$a"}, {"name": "b"}]}`), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.root.ExpandAll()
+
+	// A text filter that happens to start with $ still filters by text
+	m.enterFilterMode()
+	m.filter = "$a"
+	m.applyInput()
+	if m.isJSONPathFilter() {
+		t.Error("text filter taken for a JSONPath query")
+	}
+	for _, n := range m.viewNodes {
+		if strings.HasPrefix(n.Path, "$.items[1]") {
+			t.Errorf("text filter shows %s", n.Path)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "Active Filter: $a") {
+		t.Errorf("view does not show the text filter:\n%s", view)
+	}
+
+	m.resetView()
+	m.enterJSONPathMode()
+	m.filter = "$.items[1]"
+	m.applyInput()
+	if !m.isJSONPathFilter() {
+		t.Error("JSONPath query taken for a text filter")
+	}
+	m.rebuildTree()
+	if dump := dumpTree(m.root); dump != `{"name":"b"}` {
+		t.Errorf("rebuilt tree = %s, want the query result", dump)
+	}
+	if view := m.View(); !strings.Contains(view, "Active JSONPath: $.items[1]") {
+		t.Errorf("view does not show the query:\n%s", view)
+	}
+
+	m.enterSearchMode()
+	m.filter = "$"
+	m.applyInput()
+	if m.isJSONPathFilter() {
+		t.Error("search taken for a JSONPath query")
+	}
+}
@@ -0,0 +1,274 @@
+package viewer
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Bytes is the value of a byte string node. It prints as hex, which is
+// also what filtering and search match against.
+type Bytes []byte
+
+// String implements fmt.Stringer
+func (b Bytes) String() string {
+	return hex.EncodeToString(b)
+}
+
+// Base64 returns the bytes in standard base64
+func (b Bytes) Base64() string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// maxBytesShown is how many characters of a byte string are rendered
+// before it is cut short
+const maxBytesShown = 64
+
+// formatBytes renders a byte string as hex, or base64 when asBase64 is
+// set, shortened to maxBytesShown characters
+func formatBytes(b Bytes, asBase64 bool) string {
+	text := "0x" + b.String()
+	if asBase64 {
+		text = "b64:" + b.Base64()
+	}
+	if len(text) > maxBytesShown {
+		text = text[:maxBytesShown] + "…"
+	}
+	return fmt.Sprintf("%s (%d bytes)", text, len(b))
+}
+
+// binaryReader reads the values of a binary encoding such as MessagePack
+// or CBOR, remembering where the current value started for errors
+type binaryReader struct {
+	data  []byte
+	pos   int
+	start int // offset of the value being read
+}
+
+// next reads n bytes, failing with io.ErrUnexpectedEOF at the end of data
+func (r *binaryReader) next(n uint64) ([]byte, error) {
+	if n > uint64(len(r.data)-r.pos) {
+		r.pos = len(r.data)
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+// readByte reads a single byte
+func (r *binaryReader) readByte() (byte, error) {
+	b, err := r.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// uint reads a big-endian unsigned integer of 1, 2, 4 or 8 bytes
+func (r *binaryReader) uint(size int) (uint64, error) {
+	b, err := r.next(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// count checks a declared element count against the input left, as every
+// element takes at least min bytes, so that corrupt lengths fail early
+// instead of allocating
+func (r *binaryReader) count(n uint64, min int) (int, error) {
+	if n > uint64(len(r.data)-r.pos)/uint64(min) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return int(n), nil
+}
+
+// fail places an error at the start of the value being read
+func (r *binaryReader) fail(err error) error {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = errors.New("unexpected end of data")
+	}
+	return fmt.Errorf("byte %d: %w", r.start, err)
+}
+
+// floatNumber returns a float as a number literal, with the names used
+// elsewhere for NaN and the infinities
+func floatNumber(f float64, bits int) json.Number {
+	switch {
+	case math.IsNaN(f):
+		return json.Number("NaN")
+	case math.IsInf(f, 1):
+		return json.Number("Infinity")
+	case math.IsInf(f, -1):
+		return json.Number("-Infinity")
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, bits))
+}
+
+// binaryKey renders a map key as an object key. Binary formats allow keys
+// of any type; those other than strings are shown as they would render.
+func binaryKey(n *Node) string {
+	switch n.Type {
+	case StringNode:
+		return n.Value.(string)
+	case NullNode:
+		return "null"
+	case ObjectNode, ArrayNode:
+		out, _ := json.Marshal(orderedValue(n))
+		return string(out)
+	}
+	return fmt.Sprintf("%v", n.Value)
+}
+
+// addBinaryMember adds a map entry, decoded with its path under node, to
+// an object node. A repeated key replaces the earlier entry.
+func addBinaryMember(node *Node, value map[string]interface{}, child *Node) {
+	child.Parent = node
+	if _, dup := value[child.Key]; dup {
+		for i, existing := range node.Children {
+			if existing.Key == child.Key {
+				node.Children[i] = child
+			}
+		}
+	} else {
+		node.Children = append(node.Children, child)
+	}
+	value[child.Key] = child.Value
+}
+
+// valueDecoder decodes the next value of a binary encoding as a node
+type valueDecoder func(key, path string) (*Node, error)
+
+// readBinaryArray fills an array node with n elements read by decode, or
+// with elements until end reports true when n is negative. On error the
+// elements read so far are kept, and node is marked if the failing element
+// left nothing to show.
+func readBinaryArray(node *Node, n int, end func() (bool, error), decode valueDecoder) error {
+	values := make([]interface{}, 0, max(n, 0))
+	node.Value = values
+	for i := 0; n < 0 || i < n; i++ {
+		if n < 0 {
+			done, err := end()
+			if err != nil {
+				node.Err = err
+				return err
+			}
+			if done {
+				break
+			}
+		}
+		child, err := decode(fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", node.Path, i))
+		if child != nil {
+			child.Parent = node
+			node.Children = append(node.Children, child)
+			values = append(values, child.Value)
+			node.Value = values
+		}
+		if err != nil {
+			if child == nil {
+				node.Err = err
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// readBinaryMap fills an object node with n entries read by decode, or
+// with entries until end reports true when n is negative. Errors are
+// handled as by readBinaryArray.
+func readBinaryMap(node *Node, n int, end func() (bool, error), decode valueDecoder) error {
+	value := make(map[string]interface{}, max(n, 0))
+	node.Value = value
+	for i := 0; n < 0 || i < n; i++ {
+		if n < 0 {
+			done, err := end()
+			if err != nil {
+				node.Err = err
+				return err
+			}
+			if done {
+				break
+			}
+		}
+		k, err := decode("", node.Path)
+		if err != nil {
+			node.Err = err
+			return err
+		}
+		key := binaryKey(k)
+		child, err := decode(key, node.Path+"."+key)
+		if child != nil {
+			addBinaryMember(node, value, child)
+		}
+		if err != nil {
+			if child == nil {
+				node.Err = err
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeBinarySequence decodes values one after another until the input
+// runs out. A single value becomes the root; several are gathered under a
+// synthetic array root, like a multi-document YAML stream. When a value
+// fails to decode, the values before it are still returned.
+func decodeBinarySequence(r *binaryReader, decode valueDecoder) (*Node, error) {
+	var docs []*Node
+	var err error
+	var failed bool // whether the value that failed left nothing to show
+	for r.pos < len(r.data) {
+		var doc *Node
+		i := len(docs)
+		doc, err = decode(fmt.Sprintf("[%d]", i), fmt.Sprintf("$[%d]", i))
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+		if err != nil {
+			failed = doc == nil
+			break
+		}
+	}
+
+	if len(docs) == 1 && !failed {
+		docs[0].Key = ""
+		docs[0].rebase("$")
+		return docs[0], err
+	}
+	if len(docs) == 0 {
+		if err != nil {
+			return nil, err
+		}
+		return BuildTree(nil, "", "$"), nil
+	}
+
+	root := &Node{Type: ArrayNode, Path: "$"}
+	values := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		doc.Parent = root
+		root.Children = append(root.Children, doc)
+		values = append(values, doc.Value)
+	}
+	root.Value = values
+	if failed {
+		root.Err = err
+	}
+	return root, err
+}
@@ -0,0 +1,66 @@
+package viewer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dumpTree renders a tree compactly with the node types that JSON cannot
+// show: byte strings as b'hex', datetimes as t'...' and tags in brackets
+func dumpTree(n *Node) string {
+	var s string
+	switch n.Type {
+	case ObjectNode:
+		members := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			members[i] = fmt.Sprintf("%q:%s", child.Key, dumpTree(child))
+		}
+		s = "{" + strings.Join(members, ",") + "}"
+	case ArrayNode:
+		elements := make([]string, len(n.Children))
+		for i, child := range n.Children {
+			elements[i] = dumpTree(child)
+		}
+		s = "[" + strings.Join(elements, ",") + "]"
+	case StringNode:
+		s = fmt.Sprintf("%q", n.Value)
+	case BytesNode:
+		s = "b'" + hex.EncodeToString(n.Value.(Bytes)) + "'"
+	case DateTimeNode:
+		s = fmt.Sprintf("t'%v'", n.Value)
+	case NullNode:
+		s = "null"
+	default:
+		s = fmt.Sprintf("%v", n.Value)
+	}
+	if n.Tag != "" {
+		s += "<" + n.Tag + ">"
+	}
+	return s
+}
+
+// checkTruncations checks that every strict prefix of a valid encoding
+// fails to parse rather than panicking or succeeding
+func checkTruncations(t *testing.T, name string, data []byte, parse func([]byte) (*Node, error)) {
+	t.Helper()
+	for i := 1; i < len(data); i++ {
+		if _, err := parse(data[:i]); err == nil {
+			t.Errorf("%s truncated to %d of %d bytes: no error", name, i, len(data))
+		}
+	}
+}
+
+func TestBinaryReaderCountRejectsCorruptLengths(t *testing.T) {
+	r := &binaryReader{data: make([]byte, 10)}
+	if n, err := r.count(10, 1); err != nil || n != 10 {
+		t.Errorf("count(10, 1) = %d, %v, want 10", n, err)
+	}
+	if _, err := r.count(6, 2); err == nil {
+		t.Error("count(6, 2) with 10 bytes left: no error")
+	}
+	if _, err := r.count(1<<63, 1); err == nil {
+		t.Error("count(1<<63, 1): no error")
+	}
+}
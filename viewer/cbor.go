@@ -0,0 +1,261 @@
+package viewer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// NewFromCBOR creates a new JSON viewer from CBOR data. Maps keep their
+// entry order, byte strings become byte string nodes, date/time tags
+// become datetime nodes and bignums become numbers. Other tagged values
+// are shown annotated with their tag. A CBOR sequence of several values is
+// shown as sibling documents $[0], $[1], ...
+func NewFromCBOR(data []byte, config ...Config) (Model, error) {
+	root, err := parseCBOR(data)
+	return newFromParsed(root, err, config...)
+}
+
+// parseCBOR decodes every value in data into a Node tree
+func parseCBOR(data []byte) (*Node, error) {
+	d := &cborDecoder{binaryReader{data: data}}
+	return decodeBinarySequence(&d.binaryReader, d.decode)
+}
+
+// CBOR major types
+const (
+	cborUint = iota
+	cborNegint
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// cborIndefinite is the additional information of indefinite-length items
+const cborIndefinite = 31
+
+// cborBreak ends an indefinite-length item
+const cborBreak = 0xff
+
+// errCBORBreak is reported for a break outside an indefinite-length item
+var errCBORBreak = errors.New("unexpected break")
+
+// cborDecoder builds Node trees from CBOR data items
+type cborDecoder struct {
+	binaryReader
+}
+
+// head reads the initial byte of a data item and its argument.
+// Indefinite-length items have the additional information cborIndefinite
+// and no argument.
+func (d *cborDecoder) head() (major byte, info byte, arg uint64, err error) {
+	b, err := d.readByte()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b>>5, b&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		arg, err = d.uint(1 << (info - 24))
+		return major, info, arg, err
+	case info == cborIndefinite && major != cborUint && major != cborNegint && major != cborTag:
+		return major, info, math.MaxUint64, nil
+	}
+	return 0, 0, 0, fmt.Errorf("invalid additional information %d", info)
+}
+
+// decode reads the next data item
+func (d *cborDecoder) decode(key, path string) (*Node, error) {
+	d.start = d.pos
+	major, info, arg, err := d.head()
+	if err != nil {
+		return nil, d.fail(err)
+	}
+	indefinite := info == cborIndefinite
+
+	switch major {
+	case cborUint:
+		return BuildTree(json.Number(strconv.FormatUint(arg, 10)), key, path), nil
+	case cborNegint:
+		if arg <= math.MaxInt64 {
+			return BuildTree(json.Number(strconv.FormatInt(-1-int64(arg), 10)), key, path), nil
+		}
+		return BuildTree(cborNegative(new(big.Int).SetUint64(arg)), key, path), nil
+	case cborBytes, cborText:
+		data, err := d.str(major, arg, indefinite)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		if major == cborText && utf8.Valid(data) {
+			return BuildTree(string(data), key, path), nil
+		}
+		return &Node{Key: key, Path: path, Type: BytesNode, Value: Bytes(data)}, nil
+	case cborArray:
+		node := &Node{Key: key, Path: path, Type: ArrayNode}
+		n, err := d.length(arg, indefinite, 1)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return node, readBinaryArray(node, n, d.end, d.decode)
+	case cborMap:
+		node := &Node{Key: key, Path: path, Type: ObjectNode}
+		n, err := d.length(arg, indefinite, 2)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return node, readBinaryMap(node, n, d.end, d.decode)
+	case cborTag:
+		return d.tagged(key, path, arg)
+	}
+	return d.simple(key, path, info, arg)
+}
+
+// length returns the element count of an array or map, or -1 for an
+// indefinite-length one
+func (d *cborDecoder) length(arg uint64, indefinite bool, min int) (int, error) {
+	if indefinite {
+		return -1, nil
+	}
+	return d.count(arg, min)
+}
+
+// end reports whether the next byte is the break that closes an
+// indefinite-length item, consuming it if so
+func (d *cborDecoder) end() (bool, error) {
+	if d.pos >= len(d.data) {
+		d.start = d.pos
+		return false, d.fail(errors.New("unexpected end of data, expected break"))
+	}
+	if d.data[d.pos] == cborBreak {
+		d.pos++
+		return true, nil
+	}
+	return false, nil
+}
+
+// str reads the content of a byte or text string. An indefinite-length
+// string is the concatenation of definite-length chunks of the same type.
+func (d *cborDecoder) str(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		data, err := d.next(arg)
+		return append([]byte(nil), data...), err
+	}
+
+	var data []byte
+	for {
+		if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+			d.pos++
+			return data, nil
+		}
+		chunkMajor, info, n, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || info == cborIndefinite {
+			return nil, errors.New("invalid chunk in indefinite-length string")
+		}
+		chunk, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+	}
+}
+
+// tagged reads the data item under a tag. Date/time tags become datetime
+// nodes and bignum tags become numbers; any other tag is shown as an
+// annotation on the item.
+func (d *cborDecoder) tagged(key, path string, tag uint64) (*Node, error) {
+	node, err := d.decode(key, path)
+	if err != nil || node == nil {
+		return node, err
+	}
+
+	switch {
+	case tag == 0 && node.Type == StringNode:
+		node.Type = DateTimeNode
+		return node, nil
+	case tag == 1 && node.Type == NumberNode:
+		f, err := node.Value.(json.Number).Float64()
+		if err == nil {
+			sec, frac := math.Modf(f)
+			t := time.Unix(int64(sec), int64(frac*1e9)).UTC()
+			return &Node{Key: key, Path: path, Type: DateTimeNode, Value: t.Format(time.RFC3339Nano)}, nil
+		}
+	case (tag == 2 || tag == 3) && node.Type == BytesNode:
+		n := new(big.Int).SetBytes(node.Value.(Bytes))
+		if tag == 3 {
+			return BuildTree(cborNegative(n), key, path), nil
+		}
+		return BuildTree(json.Number(n.String()), key, path), nil
+	case tag == 55799:
+		// The self-described CBOR marker carries no meaning of its own
+		return node, nil
+	}
+
+	node.Tag = joinComments(fmt.Sprintf("tag %d", tag), node.Tag)
+	return node, nil
+}
+
+// simple reads a simple value or float
+func (d *cborDecoder) simple(key, path string, info byte, arg uint64) (*Node, error) {
+	switch info {
+	case 20:
+		return BuildTree(false, key, path), nil
+	case 21:
+		return BuildTree(true, key, path), nil
+	case 22:
+		return BuildTree(nil, key, path), nil
+	case 23:
+		node := BuildTree(nil, key, path)
+		node.Tag = "undefined"
+		return node, nil
+	case 25:
+		return BuildTree(floatNumber(float16(uint16(arg)), 32), key, path), nil
+	case 26:
+		return BuildTree(floatNumber(float64(math.Float32frombits(uint32(arg))), 32), key, path), nil
+	case 27:
+		return BuildTree(floatNumber(math.Float64frombits(arg), 64), key, path), nil
+	case cborIndefinite:
+		return nil, d.fail(errCBORBreak)
+	}
+	node := BuildTree(json.Number(strconv.FormatUint(arg, 10)), key, path)
+	node.Tag = "simple"
+	return node, nil
+}
+
+// cborNegative returns -1-n, the value of a negative integer or negative
+// bignum with argument n
+func cborNegative(n *big.Int) json.Number {
+	return json.Number(n.Neg(n).Sub(n, big.NewInt(1)).String())
+}
+
+// float16 converts an IEEE 754 half-precision float
+func float16(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+	return sign * math.Ldexp(mant+1024, exp-25)
+}
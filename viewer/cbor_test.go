@@ -0,0 +1,186 @@
+package viewer
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// encodeCBOR encodes v with the reference encoder, with map keys sorted so
+// that entry order is known
+func encodeCBOR(t *testing.T, v interface{}, opts cbor.EncOptions) []byte {
+	t.Helper()
+	opts.Sort = cbor.SortBytewiseLexical
+	em, err := opts.EncMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := em.Marshal(v)
+	if err != nil {
+		t.Fatalf("encoding %v: %v", v, err)
+	}
+	return data
+}
+
+func TestParseCBORRoundTrip(t *testing.T) {
+	shortest := cbor.EncOptions{ShortestFloat: cbor.ShortestFloat16, BigIntConvert: cbor.BigIntConvertNone}
+	wide := cbor.EncOptions{ShortestFloat: cbor.ShortestFloatNone}
+	bigPos, _ := new(big.Int).SetString("18446744073709551616", 10)
+	bigNeg, _ := new(big.Int).SetString("-18446744073709551617", 10)
+	long := strings.Repeat("x", 70000)
+	when := time.Unix(1718000000, 0)
+
+	tests := []struct {
+		value interface{}
+		opts  cbor.EncOptions
+		want  string
+	}{
+		{nil, shortest, "null"},
+		{true, shortest, "true"},
+		{false, shortest, "false"},
+		{0, shortest, "0"},
+		{23, shortest, "23"},
+		{24, shortest, "24"},
+		{255, shortest, "255"},
+		{256, shortest, "256"},
+		{65536, shortest, "65536"},
+		{uint64(1) << 32, shortest, "4294967296"},
+		{uint64(math.MaxUint64), shortest, "18446744073709551615"},
+		{-1, shortest, "-1"},
+		{-24, shortest, "-24"},
+		{-25, shortest, "-25"},
+		{-257, shortest, "-257"},
+		{int64(math.MinInt64), shortest, "-9223372036854775808"},
+		{bigPos, shortest, "18446744073709551616"},
+		{bigNeg, shortest, "-18446744073709551617"},
+		{1.5, shortest, "1.5"},
+		{65504.0, shortest, "65504"},
+		{math.Copysign(0, -1), shortest, "-0"},
+		{0.1, shortest, "0.1"},
+		{float32(0.1), wide, "0.1"},
+		{0.1, wide, "0.1"},
+		{math.NaN(), shortest, "NaN"},
+		{math.Inf(1), shortest, "Infinity"},
+		{math.Inf(-1), wide, "-Infinity"},
+		{"", shortest, `""`},
+		{"héllo", shortest, `"héllo"`},
+		{strings.Repeat("a", 24), shortest, fmt.Sprintf("%q", strings.Repeat("a", 24))},
+		{strings.Repeat("a", 256), shortest, fmt.Sprintf("%q", strings.Repeat("a", 256))},
+		{long, shortest, fmt.Sprintf("%q", long)},
+		{[]byte{}, shortest, "b''"},
+		{[]byte{1, 2, 3}, shortest, "b'010203'"},
+		{make([]byte, 300), shortest, "b'" + strings.Repeat("00", 300) + "'"},
+		{[]interface{}{}, shortest, "[]"},
+		{[]interface{}{1, "a", nil, []interface{}{true}}, shortest, `[1,"a",null,[true]]`},
+		{make([]int, 30), shortest, "[" + strings.TrimSuffix(strings.Repeat("0,", 30), ",") + "]"},
+		{map[string]interface{}{"b": 1, "a": []interface{}{}}, shortest, `{"a":[],"b":1}`},
+		{map[int]string{-1: "neg", 1: "x"}, shortest, `{"1":"x","-1":"neg"}`},
+		{map[string]interface{}{}, shortest, "{}"},
+		{when, cbor.EncOptions{Time: cbor.TimeUnix, TimeTag: cbor.EncTagRequired}, "t'2024-06-10T06:13:20Z'"},
+		{when.Add(500 * time.Millisecond), cbor.EncOptions{Time: cbor.TimeUnixMicro, TimeTag: cbor.EncTagRequired}, "t'2024-06-10T06:13:20.5Z'"},
+		{when.UTC(), cbor.EncOptions{Time: cbor.TimeRFC3339, TimeTag: cbor.EncTagRequired}, "t'2024-06-10T06:13:20Z'"},
+		{cbor.Tag{Number: 32, Content: "https://example.com"}, shortest, `"https://example.com"<tag 32>`},
+		{cbor.Tag{Number: 55799, Content: 1}, shortest, "1"},
+		{cbor.SimpleValue(16), shortest, "16<simple>"},
+		{cbor.SimpleValue(255), shortest, "255<simple>"},
+	}
+	for _, tt := range tests {
+		data := encodeCBOR(t, tt.value, tt.opts)
+		name := fmt.Sprintf("%T %x", tt.value, data)
+		if len(name) > 60 {
+			name = name[:60]
+		}
+
+		root, err := parseCBOR(data)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got := dumpTree(root); got != tt.want {
+			t.Errorf("%s: got %.80s, want %.80s", name, got, tt.want)
+		}
+		checkTruncations(t, name, data, parseCBOR)
+	}
+}
+
+func TestParseCBOREncodings(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want string
+	}{
+		{"5f42010243030405ff", "b'0102030405'"}, // indefinite byte string
+		{"7f62616263636465ff", `"abcde"`},       // indefinite text string
+		{"7fff", `""`},                          // empty indefinite text string
+		{"9f0102ff", "[1,2]"},                   // indefinite array
+		{"9f9fffff", "[[]]"},                    // nested indefinite arrays
+		{"bf616101616280ff", `{"a":1,"b":[]}`},  // indefinite map
+		{"f93c00", "1"},                         // half-precision float
+		{"f90001", "5.9604645e-08"},             // half-precision subnormal
+		{"f7", "null<undefined>"},               // undefined
+		{"62fffe", "b'fffe'"},                   // text that is not UTF-8
+		{"0102", "[1,2]"},                       // sequence of two values
+		{"a2616101616102", `{"a":2}`},           // repeated key, later wins
+		{"d8204100", "b'00'<tag 32>"},           // unknown tag on bytes
+	}
+	for _, tt := range tests {
+		data, err := hex.DecodeString(tt.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := parseCBOR(data)
+		if err != nil {
+			t.Errorf("%s: %v", tt.hex, err)
+			continue
+		}
+		if got := dumpTree(root); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.hex, got, tt.want)
+		}
+	}
+}
+
+func TestParseCBORErrors(t *testing.T) {
+	tests := []struct {
+		hex     string
+		partial string // the tree returned alongside the error, if any
+		err     string
+	}{
+		{"ff", "", "byte 0: unexpected break"},
+		{"1c", "", "byte 0: invalid additional information 28"},
+		{"1f", "", "byte 0: invalid additional information 31"},
+		{"3f", "", "byte 0: invalid additional information 31"},
+		{"df", "", "byte 0: invalid additional information 31"},
+		{"9f01", "[1]", "byte 2: unexpected end of data, expected break"},
+		{"bf6161", "{}", "byte 3: unexpected end of data"},
+		{"5f41016101ff", "", "byte 0: invalid chunk in indefinite-length string"},
+		{"5f5fffff", "", "byte 0: invalid chunk in indefinite-length string"},
+		{"5f4101", "", "byte 0: unexpected end of data"},
+		{"830102", "", "byte 0: unexpected end of data"},
+		{"9bffffffffffffffff00", "", "byte 0: unexpected end of data"},
+		{"bbffffffffffffffff0000", "", "byte 0: unexpected end of data"},
+		{"5bffffffffffffffff00", "", "byte 0: unexpected end of data"},
+		{"7affffffff61", "", "byte 0: unexpected end of data"},
+		{"8201a1", "[1]", "byte 2: unexpected end of data"},
+		{"a3616101616280", `{"a":1,"b":[]}`, "byte 7: unexpected end of data"},
+		{"01ff", "[1]", "byte 1: unexpected break"},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.hex)
+		root, err := parseCBOR(data)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("%s: error %v, want %s", tt.hex, err, tt.err)
+		}
+		got := ""
+		if root != nil {
+			got = dumpTree(root)
+		}
+		if got != tt.partial {
+			t.Errorf("%s: partial tree %s, want %q", tt.hex, got, tt.partial)
+		}
+	}
+}
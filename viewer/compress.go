@@ -0,0 +1,41 @@
+package viewer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+)
+
+// Decompress sniffs the magic bytes at the start of reader and, for gzip
+// or bzip2 input, returns a reader of the decompressed data along with the
+// format's name. Other input is returned unchanged with an empty name.
+func Decompress(reader io.Reader) (io.Reader, string, error) {
+	br := bufio.NewReader(reader)
+	magic, _ := br.Peek(3)
+
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, "gzip", err
+		}
+		return gz, "gzip", nil
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return bzip2.NewReader(br), "bzip2", nil
+	}
+	return br, "", nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -1,5 +1,7 @@
 package viewer
 
+import "time"
+
 // DefaultConfig returns a default configuration
 func DefaultConfig() Config {
 	return Config{
@@ -12,6 +14,7 @@ func DefaultConfig() Config {
 		EnableClipboard:   true,
 		Width:             0, // 0 means auto-size
 		Height:            0, // 0 means auto-size
+		ChangeHighlight:   5 * time.Second,
 		
 		// Default no-op callbacks
 		OnSelect:   func(*Node) {},
@@ -66,6 +69,12 @@ func (c Config) WithFilter(onFilter func(string)) Config {
 	return c
 }
 
+// WithReload sets the function that loads the source again
+func (c Config) WithReload(reload func() (Model, error)) Config {
+	c.Reload = reload
+	return c
+}
+
 // WithError sets error callback
 func (c Config) WithError(onError func(error)) Config {
 	if onError != nil {
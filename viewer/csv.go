@@ -0,0 +1,157 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// NewFromCSV creates a new JSON viewer from CSV or, with
+// Config.CSVDelimiter set to a tab, TSV data. The header row supplies the
+// keys and every other row becomes an object, so that filters and JSONPath
+// queries work on tabular data. With Config.InferTypes, numbers, booleans
+// and empty fields are read as numbers, booleans and null.
+func NewFromCSV(reader io.Reader, config ...Config) (Model, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Model{}, err
+	}
+
+	cfg := DefaultConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	root, err := parseCSV(data, cfg.CSVDelimiter, cfg.InferTypes)
+	return newFromParsed(root, err, config...)
+}
+
+// parseCSV builds an array of row objects. A malformed row stops parsing,
+// returning the rows before it alongside the error.
+func parseCSV(data []byte, delimiter rune, infer bool) (*Node, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	r := csv.NewReader(bytes.NewReader(data))
+	if delimiter != 0 {
+		r.Comma = delimiter
+	}
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = delimiter == '\t'
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return BuildTree(make([]interface{}, 0), "", "$"), nil
+	}
+	if err != nil {
+		return nil, newCSVParseError(data, err)
+	}
+	keys := csvKeys(header)
+
+	root := &Node{Type: ArrayNode, Path: "$"}
+	rows := make([]interface{}, 0)
+	root.Value = rows
+	for i := 0; ; i++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			root.Err = newCSVParseError(data, err)
+			return root, root.Err
+		}
+
+		row := csvRow(record, keys, infer, fmt.Sprintf("[%d]", i), fmt.Sprintf("$[%d]", i))
+		row.Parent = root
+		root.Children = append(root.Children, row)
+		rows = append(rows, row.Value)
+		root.Value = rows
+	}
+	return root, nil
+}
+
+// csvKeys turns a header row into unique object keys, naming blank
+// columns by position
+func csvKeys(header []string) []string {
+	keys := make([]string, len(header))
+	seen := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("column%d", i+1)
+		}
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, seen[name])
+		}
+		keys[i] = name
+	}
+	return keys
+}
+
+// csvRow builds the object for one record. Fields beyond the header are
+// keyed by position; missing trailing fields are left out.
+func csvRow(record, keys []string, infer bool, key, path string) *Node {
+	node := &Node{Key: key, Path: path, Type: ObjectNode}
+	value := make(map[string]interface{}, len(record))
+	for i, field := range record {
+		name := fmt.Sprintf("column%d", i+1)
+		if i < len(keys) {
+			name = keys[i]
+		}
+
+		var v interface{} = field
+		if infer {
+			v = inferCSVValue(field)
+		}
+		child := BuildTree(v, name, path+"."+name)
+		child.Parent = node
+		node.Children = append(node.Children, child)
+		value[name] = v
+	}
+	node.Value = value
+	return node
+}
+
+// inferCSVValue reads a field as a number, boolean or null where it
+// clearly is one. Numbers with leading zeros, such as postcodes and
+// account numbers, stay strings.
+func inferCSVValue(field string) interface{} {
+	trimmed := strings.TrimSpace(field)
+	switch {
+	case trimmed == "":
+		return nil
+	case strings.EqualFold(trimmed, "true"):
+		return true
+	case strings.EqualFold(trimmed, "false"):
+		return false
+	case (trimmed[0] == '-' || (trimmed[0] >= '0' && trimmed[0] <= '9')) && json.Valid([]byte(trimmed)):
+		return json.Number(trimmed)
+	}
+	return field
+}
+
+// newCSVParseError places a CSV error at the line and column it names
+func newCSVParseError(data []byte, err error) error {
+	var csvErr *csv.ParseError
+	if !errors.As(err, &csvErr) {
+		return err
+	}
+
+	offset := 0
+	for line := 1; line < csvErr.Line && offset < len(data); line++ {
+		next := bytes.IndexByte(data[offset:], '\n')
+		if next < 0 {
+			offset = len(data)
+			break
+		}
+		offset += next + 1
+	}
+	// Column counts runes from 1; step over them to find the byte offset
+	for column := 1; column < csvErr.Column && offset < len(data); column++ {
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return newParseErrorAt(data, int64(offset), csvErr.Err)
+}
@@ -0,0 +1,242 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// parseJSON decodes a single JSON document into a Node tree. Unlike
+// json.Unmarshal it walks the token stream, so object members keep the
+// order in which they appear in the source, and numbers are kept as their
+// exact json.Number literal. Syntax errors are returned as *ParseError.
+//
+// When the document is malformed, the tree parsed up to the error is still
+// returned (if any), with the node where parsing stopped carrying the error.
+func parseJSON(data []byte, key, path string) (*Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	d := &jsonDecoder{dec: dec, source: data}
+
+	node, err := d.decodeNode(key, path)
+	if err != nil {
+		return node, err
+	}
+
+	end := d.dec.InputOffset()
+	if rest := bytes.TrimLeft(data[end:], " \t\r\n"); len(rest) > 0 {
+		node.Err = newParseErrorAt(data, int64(len(data)-len(rest)), ErrTrailingData)
+		return node, node.Err
+	}
+	return node, nil
+}
+
+// tokenStream is the part of *json.Decoder that jsonDecoder consumes, so
+// that relaxed syntaxes such as JSON5 can supply their own tokens
+type tokenStream interface {
+	Token() (json.Token, error)
+	More() bool
+	InputOffset() int64
+}
+
+// commentStream is implemented by token streams that keep comments
+type commentStream interface {
+	// Comment returns the comments that preceded the last token read
+	Comment() string
+}
+
+// jsonDecoder builds Node trees from a JSON token stream
+type jsonDecoder struct {
+	dec    tokenStream
+	source []byte
+
+	// tee, when set, collects the input of a streaming decode and is used
+	// as the source for error positions
+	tee *bytes.Buffer
+}
+
+// comment returns the comments preceding the last token, if kept
+func (d *jsonDecoder) comment() string {
+	if cs, ok := d.dec.(commentStream); ok {
+		return cs.Comment()
+	}
+	return ""
+}
+
+// fail records a decoder error on the node where parsing stopped
+func (d *jsonDecoder) fail(node *Node, err error) (*Node, error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	source := d.source
+	if d.tee != nil {
+		source = d.tee.Bytes()
+	}
+	perr := newParseError(source, err)
+	if node != nil {
+		node.Err = perr
+	}
+	return node, perr
+}
+
+// errorPlaceholder stands in for a member or element whose value failed to
+// parse, as a null carrying the error, so that the error is shown where
+// parsing stopped
+func errorPlaceholder(key, path string, err error) *Node {
+	node := BuildTree(nil, key, path)
+	node.Err = err
+	return node
+}
+
+// decodeNode reads the next value and builds its subtree
+func (d *jsonDecoder) decodeNode(key, path string) (*Node, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return d.fail(nil, err)
+	}
+	comment := d.comment()
+
+	var node *Node
+	switch tok {
+	case json.Delim('{'):
+		node, err = d.decodeObject(key, path)
+	case json.Delim('['):
+		node, err = d.decodeArray(key, path)
+	default:
+		node = BuildTree(tok, key, path)
+	}
+	node.Comment = comment
+	return node, err
+}
+
+// decodeObject reads object members up to and including the closing brace
+func (d *jsonDecoder) decodeObject(key, path string) (*Node, error) {
+	node := &Node{Key: key, Path: path, Type: ObjectNode}
+	value := make(map[string]interface{})
+	node.Value = value
+
+	for d.dec.More() {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return d.fail(node, err)
+		}
+		k := tok.(string)
+		comment := d.comment()
+
+		child, err := d.decodeNode(k, path+"."+k)
+		if child == nil {
+			child = errorPlaceholder(k, path+"."+k, err)
+		}
+		child.Parent = node
+		if comment != "" {
+			child.Comment = strings.TrimSpace(comment + " " + child.Comment)
+		}
+
+		// Later duplicates win, as with json.Unmarshal
+		if _, dup := value[k]; dup {
+			for i, c := range node.Children {
+				if c.Key == k {
+					node.Children[i] = child
+				}
+			}
+		} else {
+			node.Children = append(node.Children, child)
+		}
+		value[k] = child.Value
+
+		if err != nil {
+			return node, err
+		}
+	}
+
+	if _, err := d.dec.Token(); err != nil {
+		return d.fail(node, err)
+	}
+	return node, nil
+}
+
+// decodeArray reads array elements up to and including the closing bracket
+func (d *jsonDecoder) decodeArray(key, path string) (*Node, error) {
+	node := &Node{Key: key, Path: path, Type: ArrayNode}
+	value := make([]interface{}, 0)
+	node.Value = value
+
+	for i := 0; d.dec.More(); i++ {
+		childKey, childPath := fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", path, i)
+		child, err := d.decodeNode(childKey, childPath)
+		if child == nil {
+			child = errorPlaceholder(childKey, childPath, err)
+		}
+		child.Parent = node
+		node.Children = append(node.Children, child)
+		value = append(value, child.Value)
+		node.Value = value
+
+		if err != nil {
+			return node, err
+		}
+	}
+
+	if _, err := d.dec.Token(); err != nil {
+		return d.fail(node, err)
+	}
+	return node, nil
+}
+
+// orderedValue returns the node's value with objects encoded in the order
+// of the node's children rather than Go's sorted map order. NaN and the
+// infinities become strings, which JSON can hold.
+func orderedValue(n *Node) interface{} {
+	if n.Raw != "" {
+		return n.Raw
+	}
+	switch n.Type {
+	case ObjectNode:
+		members := make(orderedObject, 0, len(n.Children))
+		for _, child := range n.Children {
+			members = append(members, objectMember{Key: child.Key, Value: orderedValue(child)})
+		}
+		return members
+	case ArrayNode:
+		elements := make([]interface{}, 0, len(n.Children))
+		for _, child := range n.Children {
+			elements = append(elements, orderedValue(child))
+		}
+		return elements
+	}
+	return serializableNumber(n.Value)
+}
+
+// orderedObject is a JSON object that marshals its members in slice order
+type orderedObject []objectMember
+
+type objectMember struct {
+	Key   string
+	Value interface{}
+}
+
+// MarshalJSON implements json.Marshaler
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
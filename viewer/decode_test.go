@@ -0,0 +1,67 @@
+package viewer
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestOrderedValueMarshalsNonFiniteNumbers(t *testing.T) {
+	json5Root, err := parseJSON5([]byte(`{a: Infinity, b: -Infinity, c: NaN, d: [1.5, +Infinity]}`), "", "$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		root *Node
+		want string
+	}{
+		{"json5", json5Root, `{"a":"Infinity","b":"-Infinity","c":"NaN","d":[1.5,"Infinity"]}`},
+		{"float64", BuildTree(map[string]interface{}{"x": math.NaN(), "y": math.Inf(-1)}, "", "$"), `{"x":"NaN","y":"-Infinity"}`},
+		{"reflect", BuildTree(struct{ F float32 }{float32(math.Inf(1))}, "", "$"), `{"F":"Infinity"}`},
+	}
+	for _, tt := range tests {
+		out, err := json.Marshal(orderedValue(tt.root))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(out) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, out, tt.want)
+		}
+	}
+}
+
+func TestParseJSONMarksWhereParsingStopped(t *testing.T) {
+	tests := []struct {
+		input   string
+		errPath string
+	}{
+		{`{"a":1,"b":tru`, "$.b"},
+		{`{"a":1,"b":`, "$.b"},
+		{`{"a":{"c":[1,2,x]}}`, "$.a.c[2]"},
+		{`[1,"two",nul`, "$[2]"},
+		{`{"a":1,"b"`, "$.b"},
+		{`{"a":1 "b":2}`, "$"},
+	}
+	for _, tt := range tests {
+		root, err := parseJSON([]byte(tt.input), "", "$")
+		if err == nil || root == nil {
+			t.Errorf("%s: got %v, %v, want a partial tree and an error", tt.input, root, err)
+			continue
+		}
+		node := findErrorNode(root)
+		if node == nil || node.Path != tt.errPath {
+			t.Errorf("%s: error on %v, want %s", tt.input, node, tt.errPath)
+			continue
+		}
+		if node.Err != err {
+			t.Errorf("%s: node error %v, want %v", tt.input, node.Err, err)
+		}
+	}
+
+	root, _ := parseJSON([]byte(`{"a":1,"b":tru`), "", "$")
+	if len(root.Children) != 2 || root.Children[0].Value != json.Number("1") || root.Err != nil {
+		t.Errorf("partial object = %v with error %v, want a and b with the error on b", root.Value, root.Err)
+	}
+}
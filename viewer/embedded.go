@@ -0,0 +1,112 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// embeddedJSONTag marks nodes decoded from JSON held in a string
+const embeddedJSONTag = "json"
+
+// expandStrings replaces string values under n that hold encoded data
+// with the decoded value, as enabled by Config.ExpandEmbeddedJSON and
+// Config.DecodeBase64, so that it can be expanded, searched and queried
+// like the rest of the tree. The string is kept in Raw, which is what
+// copying gives, and the node is tagged with what it was decoded from.
+// It reports whether n's value changed. Containers are copied before a
+// decoded value is written into them, since a tree built by New holds the
+// caller's own maps and slices.
+func expandStrings(n *Node, config Config) bool {
+	changed := false
+	if n.Type == StringNode {
+		switch {
+		case config.ExpandEmbeddedJSON && decodeEmbeddedJSON(n):
+			changed = true
+		case config.DecodeBase64 && decodeJWT(n):
+			changed = true
+		case config.DecodeBase64 && decodeBase64(n):
+			changed = true
+		}
+	}
+	copied := false
+	for i, child := range n.Children {
+		if !expandStrings(child, config) {
+			continue
+		}
+		if !copied {
+			n.Value = copyContainer(n.Value)
+			copied = true
+		}
+		updateChildValue(n, i)
+	}
+	return changed || copied
+}
+
+// copyContainer returns a shallow copy of an object or array value
+func copyContainer(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			c[k] = e
+		}
+		return c
+	case []interface{}:
+		return append([]interface{}(nil), v...)
+	}
+	return value
+}
+
+// updateChildValue copies a decoded child's value into its parent's value,
+// which is what JSONPath queries see. The parent's container is written in
+// place, so it must not be one the caller passed in.
+func updateChildValue(parent *Node, i int) {
+	child := parent.Children[i]
+	switch v := parent.Value.(type) {
+	case map[string]interface{}:
+		v[child.Key] = child.Value
+	case []interface{}:
+		v[i] = child.Value
+	}
+}
+
+// decodeEmbeddedJSON turns a string node holding a JSON object or array
+// into that value, reporting whether it did
+func decodeEmbeddedJSON(n *Node) bool {
+	s, _ := n.Value.(string)
+	data := bytes.TrimSpace([]byte(s))
+	if len(data) < 2 || !isJSONContainer(data[0], data[len(data)-1]) || !json.Valid(data) {
+		return false
+	}
+	parsed, err := parseJSON(data, n.Key, n.Path)
+	if err != nil {
+		return false
+	}
+
+	n.Type = parsed.Type
+	n.Value = parsed.Value
+	n.Children = parsed.Children
+	for _, child := range n.Children {
+		child.Parent = n
+	}
+	n.Raw = s
+	n.Tag = joinComments(n.Tag, embeddedJSONTag)
+	return true
+}
+
+// isJSONContainer reports whether first and last delimit an object or array
+func isJSONContainer(first, last byte) bool {
+	return (first == '{' && last == '}') || (first == '[' && last == ']')
+}
+
+// formatRaw renders the original string of a decoded node, shortened to
+// fit on its line
+func formatRaw(raw string) string {
+	const maxRawShown = 60
+	runes := []rune(raw)
+	if len(runes) > maxRawShown {
+		raw = string(runes[:maxRawShown]) + "…"
+	}
+	return fmt.Sprintf("%q", raw)
+}
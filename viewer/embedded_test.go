@@ -0,0 +1,129 @@
+package viewer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// embeddedModel creates a viewer with embedded JSON expanded, everything
+// shown and copies recorded in copied
+func embeddedModel(t *testing.T, data []byte, copied *string) Model {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.ExpandEmbeddedJSON = true
+	cfg.EnableClipboard = true
+	cfg.OnCopy = func(value string) { *copied = value }
+	m, err := NewFromJSON(data, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// moveTo puts the cursor on the node at path
+func (m *Model) moveTo(t *testing.T, path string) *Node {
+	t.Helper()
+	m.updateViewNodes()
+	for i, n := range m.viewNodes {
+		if n.Path == path {
+			m.cursor = i
+			return n
+		}
+	}
+	t.Fatalf("no node at %s", path)
+	return nil
+}
+
+func TestEmbeddedJSONRendersRawUntilExpanded(t *testing.T) {
+	var copied string
+	m := embeddedModel(t, []byte(`{"payload": "{\"a\":1,\"b\":[true]}"}`), &copied)
+	m.root.Expanded = true
+	node := m.moveTo(t, "$.payload")
+
+	line := m.renderNode(node, false)
+	if !strings.Contains(line, `"{\"a\":1,\"b\":[true]}"`) || !strings.Contains(line, "(json)") {
+		t.Errorf("collapsed line = %q, want the raw string marked (json)", line)
+	}
+	if strings.Contains(line, "{...}") {
+		t.Errorf("collapsed line = %q, want no object summary", line)
+	}
+
+	node.Expanded = true
+	line = m.renderNode(node, false)
+	if strings.Contains(line, `\"a\"`) || !strings.Contains(line, "{") || !strings.Contains(line, "(json)") {
+		t.Errorf("expanded line = %q, want an open object marked (json)", line)
+	}
+	m.moveTo(t, "$.payload.b")
+	if dump := dumpTree(node); dump != `{"a":1,"b":[true]}<json>` {
+		t.Errorf("payload = %s", dump)
+	}
+}
+
+func TestEmbeddedJSONCopiesRaw(t *testing.T) {
+	var copied string
+	m := embeddedModel(t, []byte(`{"payload": " {\"a\": 1} "}`), &copied)
+	m.root.Expanded = true
+
+	m.moveTo(t, "$.payload")
+	m.copyValue()
+	if want := ` {"a": 1} `; copied != want {
+		t.Errorf("copied %q, want the original string %q", copied, want)
+	}
+
+	m.viewNodes[m.cursor].Expanded = true
+	m.moveTo(t, "$.payload.a")
+	m.copyValue()
+	if copied != "1" {
+		t.Errorf("copied %q from inside the payload, want 1", copied)
+	}
+}
+
+func TestEmbeddedJSONNested(t *testing.T) {
+	data := map[string]interface{}{
+		"outer":  `{"inner": "[1, {\"deep\": true}]", "plain": "[not json"}`,
+		"nested": []interface{}{map[string]interface{}{"inner": `{"b":true}`}},
+	}
+	want := map[string]interface{}{
+		"outer":  `{"inner": "[1, {\"deep\": true}]", "plain": "[not json"}`,
+		"nested": []interface{}{map[string]interface{}{"inner": `{"b":true}`}},
+	}
+
+	cfg := DefaultConfig()
+	cfg.ExpandEmbeddedJSON = true
+	m := New(data, cfg)
+
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("input changed to %#v", data)
+	}
+	wantTree := `{"nested":[{"inner":{"b":true}<json>}],"outer":{"inner":[1,{"deep":true}]<json>,"plain":"[not json"}<json>}`
+	if dump := dumpTree(m.source); dump != wantTree {
+		t.Errorf("tree = %s\nwant %s", dump, wantTree)
+	}
+	got, err := queryJSONPath("$.outer.inner[1].deep", m.rawData)
+	if err != nil || got != true {
+		t.Errorf("$.outer.inner[1].deep = %v, %v, want true", got, err)
+	}
+}
+
+func TestEmbeddedJSONInAppendedLines(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ExpandEmbeddedJSON = true
+	m, err := NewFromJSONLines(strings.NewReader(`{"msg": "{\"n\":1}"}`+"\n"), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.appendLines([][]byte{[]byte(`{"msg": "{\"n\":2}"}` + "\n")})
+
+	if dump := dumpTree(m.root); dump != `[{"msg":{"n":1}<json>},{"msg":{"n":2}<json>}]` {
+		t.Errorf("tree = %s", dump)
+	}
+	if msg := m.root.Children[1].Children[0]; msg.Raw != `{"n":2}` {
+		t.Errorf("appended Raw = %q", msg.Raw)
+	}
+	got, err := queryJSONPath("$[1].msg.n", m.rawData)
+	if err != nil || !valuesEqual(got, float64(2)) {
+		t.Errorf("$[1].msg.n = %v, %v, want 2", got, err)
+	}
+}
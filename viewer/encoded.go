@@ -0,0 +1,156 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// jwtPattern matches the three dot-separated base64url parts of a JWT
+var jwtPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+
+// jwtTimeClaims are the registered claims that hold a time in seconds
+// since the epoch
+var jwtTimeClaims = map[string]bool{"exp": true, "iat": true, "nbf": true, "auth_time": true}
+
+// decodeJWT turns a string node holding a JWT into an object of its
+// decoded header, payload and signature, reporting whether it did. Time
+// claims in the payload are tagged with their date.
+func decodeJWT(n *Node) bool {
+	token, _ := n.Value.(string)
+	if !jwtPattern.MatchString(token) {
+		return false
+	}
+	parts := strings.Split(token, ".")
+
+	header, ok := decodeJWTPart(parts[0], "header", n.Path)
+	if !ok || header.Type != ObjectNode {
+		return false
+	}
+	if _, ok := header.Value.(map[string]interface{})["alg"]; !ok {
+		return false
+	}
+	payload, ok := decodeJWTPart(parts[1], "payload", n.Path)
+	if !ok {
+		return false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+
+	if payload.Type == ObjectNode {
+		for _, claim := range payload.Children {
+			if jwtTimeClaims[claim.Key] && claim.Type == NumberNode {
+				if sec, err := claim.Value.(json.Number).Int64(); err == nil {
+					claim.Tag = joinComments(claim.Tag, time.Unix(sec, 0).UTC().Format(time.RFC3339))
+				}
+			}
+		}
+	}
+	setDecoded(n, "jwt", header, payload,
+		&Node{Key: "signature", Path: n.Path + ".signature", Type: BytesNode, Value: Bytes(signature)})
+	return true
+}
+
+// decodeJWTPart decodes the header or payload of a JWT, which is JSON
+func decodeJWTPart(part, key, path string) (*Node, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil || !json.Valid(data) {
+		return nil, false
+	}
+	node, err := parseJSON(data, key, path+"."+key)
+	return node, err == nil
+}
+
+// minBase64Length is the shortest string taken for base64, so that short
+// words and identifiers are left alone
+const minBase64Length = 12
+
+// decodeBase64 turns a string node holding base64 into an object with one
+// view of the decoded data, reporting whether it did: "json" when it is a
+// JSON object or array, "text" when it is printable UTF-8, and otherwise
+// "bytes". Binary data is only assumed for strings that have base64's
+// padding or symbols, since many plain identifiers are also valid base64.
+func decodeBase64(n *Node) bool {
+	s, _ := n.Value.(string)
+	if len(s) < minBase64Length {
+		return false
+	}
+	data, ok := decodeBase64String(s)
+	if !ok {
+		return false
+	}
+
+	var view *Node
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) >= 2 && isJSONContainer(trimmed[0], trimmed[len(trimmed)-1]) && json.Valid(trimmed):
+		parsed, err := parseJSON(trimmed, "json", n.Path+".json")
+		if err != nil {
+			return false
+		}
+		view = parsed
+	case isPrintableText(data):
+		view = &Node{Key: "text", Path: n.Path + ".text", Type: StringNode, Value: string(data)}
+	case strings.ContainsAny(s, "+/=") && len(s) >= 16:
+		view = &Node{Key: "bytes", Path: n.Path + ".bytes", Type: BytesNode, Value: Bytes(data)}
+	default:
+		return false
+	}
+
+	setDecoded(n, "base64", view)
+	return true
+}
+
+// decodeBase64String decodes standard or URL-safe base64, padded or not.
+// Strict decoding rejects stray bits, which ordinary words often have.
+func decodeBase64String(s string) ([]byte, bool) {
+	encodings := []*base64.Encoding{base64.StdEncoding, base64.URLEncoding}
+	if !strings.HasSuffix(s, "=") {
+		encodings = []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding}
+		if len(s)%4 == 0 {
+			encodings = append(encodings, base64.StdEncoding, base64.URLEncoding)
+		}
+	}
+	for _, enc := range encodings {
+		if data, err := enc.Strict().DecodeString(s); err == nil {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// isPrintableText reports whether data is UTF-8 text with no control
+// characters other than whitespace
+func isPrintableText(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// setDecoded turns a string node into an object of the values decoded
+// from it, keeping the string in Raw and tagging the node
+func setDecoded(n *Node, tag string, children ...*Node) {
+	value := make(map[string]interface{}, len(children))
+	for _, child := range children {
+		child.Parent = n
+		value[child.Key] = child.Value
+	}
+	n.Raw = n.Value.(string)
+	n.Type = ObjectNode
+	n.Value = value
+	n.Children = children
+	n.Tag = joinComments(n.Tag, tag)
+}
@@ -0,0 +1,85 @@
+package viewer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeBase64LeavesInputUnchanged(t *testing.T) {
+	encode := base64.RawURLEncoding.EncodeToString
+	jwt := encode([]byte(`{"alg":"HS256"}`)) + "." + encode([]byte(`{"sub":"1","exp":1718000000}`)) + "." + encode([]byte("sig"))
+	text := base64.StdEncoding.EncodeToString([]byte("hello, base64 world"))
+
+	data := map[string]interface{}{
+		"token":  jwt,
+		"values": []interface{}{text},
+	}
+	want := map[string]interface{}{
+		"token":  jwt,
+		"values": []interface{}{text},
+	}
+
+	cfg := DefaultConfig()
+	cfg.DecodeBase64 = true
+	m := New(data, cfg)
+
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("input changed to %#v", data)
+	}
+	viewed := m.rawData.(map[string]interface{})
+	if _, ok := viewed["token"].(map[string]interface{}); !ok {
+		t.Errorf("token in viewer data = %#v, want decoded object", viewed["token"])
+	}
+	if _, ok := viewed["values"].([]interface{})[0].(map[string]interface{}); !ok {
+		t.Errorf("base64 value in viewer data = %#v, want decoded object", viewed["values"])
+	}
+}
+
+func TestDecodeBase64Strings(t *testing.T) {
+	encode := base64.RawURLEncoding.EncodeToString
+	jwt := func(header, payload string) string {
+		return encode([]byte(header)) + "." + encode([]byte(payload)) + "." + encode([]byte("sig"))
+	}
+	std := base64.StdEncoding.EncodeToString
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"jwt", jwt(`{"alg":"HS256","typ":"JWT"}`, `{"exp":1718000000,"iat":"soon","sub":"1"}`),
+			`{"header":{"alg":"HS256","typ":"JWT"},"payload":{"exp":1718000000<2024-06-10T06:13:20Z>,"iat":"soon","sub":"1"},"signature":b'736967'}<jwt>`},
+		{"jwt without alg", jwt(`{"typ":"JWT"}`, `{"sub":"1"}`), ""},
+		{"jwt with a non-JSON payload", jwt(`{"alg":"none"}`, `not json`), ""},
+		{"json", std([]byte(`{"a":1}`)), `{"json":{"a":1}}<base64>`},
+		{"text", std([]byte("hello, base64 world")), `{"text":"hello, base64 world"}<base64>`},
+		{"url-safe text", base64.RawURLEncoding.EncodeToString([]byte("hello, base64 world")), `{"text":"hello, base64 world"}<base64>`},
+		{"bytes", std([]byte{0xfb, 0xef, 0xbe, 0, 1, 2, 0xfb, 0xef, 0xbe, 0, 1, 2}),
+			`{"bytes":b'fbefbe000102fbefbe000102'}<base64>`},
+		{"too short", std([]byte("hello")), ""},
+		{"binary without base64 symbols", std([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}), ""},
+		{"hex id", "0123456789abcdef0123", ""},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", ""},
+		{"plain word", "internationalization", ""},
+	}
+	cfg := Config{DecodeBase64: true}
+	for _, tt := range tests {
+		n := &Node{Path: "$", Type: StringNode, Value: tt.in}
+		changed := expandStrings(n, cfg)
+		want := tt.want
+		if want == "" {
+			want = fmt.Sprintf("%q", tt.in)
+		}
+		if got := dumpTree(n); got != want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, want)
+		}
+		if changed != (tt.want != "") {
+			t.Errorf("%s: changed = %v", tt.name, changed)
+		}
+		if changed && n.Raw != tt.in {
+			t.Errorf("%s: Raw = %q, want the original string", tt.name, n.Raw)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrTrailingData is reported when a document holds more than one
+// top-level value
+var ErrTrailingData = errors.New("unexpected data after top-level value")
+
+// ParseError describes where a document failed to parse
+type ParseError struct {
+	Line   int   // 1-based line of the offending input
+	Column int   // 1-based column, counted in characters; 0 when unknown
+	Offset int64 // byte offset of the offending input
+	Err    error // underlying decoder error
+
+	source   []byte
+	lineBase int // lines preceding source in the original input
+}
+
+// newParseError locates a decoder error within source. Errors that carry
+// no position are placed at the end of the input.
+func newParseError(source []byte, err error) *ParseError {
+	offset := int64(len(source))
+	var syntaxErr *json.SyntaxError
+	var relaxedErr *syntaxError
+	if errors.As(err, &syntaxErr) {
+		// The decoder reports the offset just past the offending byte
+		offset = max64(0, syntaxErr.Offset-1)
+	} else if errors.As(err, &relaxedErr) {
+		offset = relaxedErr.Offset
+	}
+	return newParseErrorAt(source, offset, err)
+}
+
+// newParseErrorAt creates a parse error for a known byte offset
+func newParseErrorAt(source []byte, offset int64, err error) *ParseError {
+	offset = min64(offset, int64(len(source)))
+	before := source[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+
+	return &ParseError{
+		Line:   bytes.Count(before, []byte{'\n'}) + 1,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+		Offset: offset,
+		Err:    err,
+		source: source,
+	}
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Position(), e.Err)
+}
+
+// Position describes where the error is, leaving out the column for
+// decoders that report only the line
+func (e *ParseError) Position() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d", e.Line)
+	}
+	return fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+}
+
+// Unwrap returns the underlying decoder error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Snippet renders the offending line, preceded by up to context lines,
+// with a caret under the offending character when the column is known
+func (e *ParseError) Snippet(context int) string {
+	if e.source == nil {
+		return ""
+	}
+
+	lines := strings.Split(string(e.source), "\n")
+	errLine := e.Line - 1 - e.lineBase
+	first := errLine - context
+	if first < 0 {
+		first = 0
+	}
+
+	width := len(fmt.Sprintf("%d", e.Line))
+	var b strings.Builder
+	for i := first; i <= errLine && i < len(lines); i++ {
+		text := strings.TrimRight(lines[i], "\r")
+		column := e.Column
+		if i != errLine {
+			column = 0
+		}
+		text, column = snippetWindow(text, column)
+		fmt.Fprintf(&b, "%*d | %s\n", width, i+1+e.lineBase, text)
+		if i == errLine && e.Column > 0 {
+			fmt.Fprintf(&b, "%*s | %s^\n", width, "", caretIndent(text, column))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// snippetWidth is the widest line shown by Snippet; minified documents are
+// cut down to a window around the error
+const snippetWidth = 100
+
+// snippetWindow shortens a long line to the part around column, returning
+// the column adjusted to the shortened text
+func snippetWindow(line string, column int) (string, int) {
+	runes := []rune(line)
+	if len(runes) <= snippetWidth {
+		return line, column
+	}
+
+	start := max(0, column-1-snippetWidth/2)
+	end := min(len(runes), start+snippetWidth)
+	start = max(0, end-snippetWidth)
+
+	text := string(runes[start:end])
+	column -= start
+	if start > 0 {
+		text = "…" + text
+		column++
+	}
+	if end < len(runes) {
+		text += "…"
+	}
+	return text, column
+}
+
+// caretIndent returns the whitespace that lines a caret up with column,
+// keeping tabs so that the caret aligns however the terminal expands them
+func caretIndent(line string, column int) string {
+	var b strings.Builder
+	for i, r := range []rune(line) {
+		if i >= column-1 {
+			break
+		}
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+// Hint suggests a likely cause for common mistakes, or returns ""
+func (e *ParseError) Hint() string {
+	msg := e.Err.Error()
+	if errors.Is(e.Err, io.ErrUnexpectedEOF) || strings.Contains(msg, "unexpected end of JSON input") {
+		return "the document ends early; look for a missing closing brace, bracket or quote"
+	}
+	if errors.Is(e.Err, ErrTrailingData) {
+		return "the input holds more than one top-level value; it may be JSON Lines or a concatenated stream"
+	}
+
+	// Without a column the offset is only the start of a line, so the
+	// bytes there say nothing about the mistake
+	var offending, previous, next byte
+	if e.Column > 0 && e.Offset < int64(len(e.source)) {
+		offending = e.source[e.Offset]
+		rest := bytes.TrimLeft(e.source[e.Offset+1:], " \t\r\n")
+		if isIdentByte(offending) {
+			rest = bytes.TrimLeft(bytes.TrimLeftFunc(e.source[e.Offset:], isIdentRune), " \t\r\n")
+		}
+		if len(rest) > 0 {
+			next = rest[0]
+		}
+	}
+	if trimmed := bytes.TrimRight(e.source[:e.Offset], " \t\r\n"); e.Column > 0 && len(trimmed) > 0 {
+		previous = trimmed[len(trimmed)-1]
+	}
+
+	switch {
+	case (offending == '}' || offending == ']') && previous == ',',
+		offending == ',' && (next == '}' || next == ']'):
+		return "trailing commas are not allowed in JSON (JSONC and JSON5 accept them)"
+	case offending == '/' || offending == '#':
+		return "comments are not allowed in JSON (JSONC and JSON5 accept them)"
+	case offending == '\'':
+		return "strings must use double quotes, not single quotes"
+	case offending == '+', strings.Contains(msg, "in numeric literal"),
+		e.afterLoneZero() && (isDigit(offending) || offending == 'x' || offending == 'X'):
+		return "numbers cannot have leading zeros, a leading '+', or hex digits"
+	case strings.Contains(msg, "after object key:value pair"), strings.Contains(msg, "after array element"):
+		return "a comma may be missing between the previous value and this one"
+	case strings.Contains(msg, "after object key"):
+		return "object keys must be followed by a colon"
+	case strings.Contains(msg, "escape"):
+		return "only \\\" \\\\ \\/ \\b \\f \\n \\r \\t and \\uXXXX escapes are valid in JSON strings"
+	case strings.Contains(msg, "in string"):
+		return "control characters such as newlines and tabs must be escaped inside strings"
+	case isIdentByte(offending) && next == ':':
+		return "object keys must be double-quoted strings"
+	case isIdentByte(offending) && !isDigit(offending):
+		return "bare words are not valid values; quote strings and use lowercase true, false or null"
+	}
+	return ""
+}
+
+// afterLoneZero reports whether the error directly follows a number that
+// is just 0 or -0, as when a number has a leading zero or a hex prefix
+func (e *ParseError) afterLoneZero() bool {
+	before := e.source[:e.Offset]
+	if !bytes.HasSuffix(before, []byte{'0'}) {
+		return false
+	}
+	before = bytes.TrimSuffix(bytes.TrimSuffix(before, []byte{'0'}), []byte{'-'})
+	return len(before) == 0 || !isIdentByte(before[len(before)-1]) && before[len(before)-1] != '.'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentByte(c byte) bool {
+	return c < utf8.RuneSelf && isIdentRune(rune(c))
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
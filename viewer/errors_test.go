@@ -0,0 +1,50 @@
+package viewer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorHint(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // a distinctive part of the hint
+	}{
+		{`[1`, "ends early"},
+		{`1 2`, "more than one top-level value"},
+		{`[1,]`, "trailing commas"},
+		{`{"a":1,}`, "trailing commas"},
+		{`[1 /* c */]`, "comments"},
+		{`['x']`, "double quotes"},
+		{`[+1]`, "leading zeros"},
+		{`[01]`, "leading zeros"},
+		{`[-01]`, "leading zeros"},
+		{`{"a":01}`, "leading zeros"},
+		{`[0x1F]`, "leading zeros"},
+		{`[1.]`, "leading zeros"},
+		{`[1 2]`, "comma may be missing"},
+		{`[10 2]`, "comma may be missing"},
+		{`["x" 2]`, "comma may be missing"},
+		{`[true false]`, "comma may be missing"},
+		{`{"a":1 "b":2}`, "comma may be missing"},
+		{`{"a" 1}`, "followed by a colon"},
+		{`["\q"]`, "escapes are valid"},
+		{"[\"a\tb\"]", "must be escaped"},
+		{`{a:1}`, "keys must be double-quoted"},
+		{`[TRUE]`, "bare words"},
+		{`[x]`, "bare words"},
+	}
+	for _, tt := range tests {
+		_, err := parseJSON([]byte(tt.input), "", "$")
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: error %v is not a ParseError", tt.input, err)
+			continue
+		}
+		hint := parseErr.Hint()
+		if !strings.Contains(hint, tt.want) {
+			t.Errorf("%s: Hint() = %q, want it to mention %q", tt.input, hint, tt.want)
+		}
+	}
+}
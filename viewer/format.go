@@ -0,0 +1,260 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Decoder turns raw input into a Node tree. When the input is malformed,
+// Decode may return the part of the tree that parsed alongside the error,
+// which is shown with Config.Lenient.
+//
+// Decoders only need to set each node's Key, Type and Children, and Value
+// for leaves; parents, paths and the values of objects and arrays are
+// filled in from the children. The value of a BytesNode may be a []byte
+// or a string.
+type Decoder interface {
+	Decode(data []byte, config Config) (*Node, error)
+}
+
+// DecoderFunc adapts a function to the Decoder interface
+type DecoderFunc func(data []byte, config Config) (*Node, error)
+
+// Decode implements Decoder
+func (f DecoderFunc) Decode(data []byte, config Config) (*Node, error) {
+	return f(data, config)
+}
+
+// Format is a registered input format
+type Format struct {
+	Name       string
+	Extensions []string // file extensions, such as ".toml", that select the format
+	Sniff      func(data []byte) bool
+	Decoder    Decoder
+}
+
+var (
+	formatsMu     sync.RWMutex
+	formats       []*Format
+	formatAliases = map[string]string{"jsonc": "json5", "yml": "yaml"}
+)
+
+// RegisterFormat adds an input format, or replaces the one of the same
+// name, so that NewFromFormat and DetectFormat know it. sniff reports
+// whether data looks like the format and may be nil; formats are sniffed
+// in the order they were registered, after the built-in ones.
+func RegisterFormat(name string, sniff func(data []byte) bool, decoder Decoder, extensions ...string) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	f := &Format{Name: name, Extensions: extensions, Sniff: sniff, Decoder: decoder}
+	for i, existing := range formats {
+		if existing.Name == name {
+			formats[i] = f
+			return
+		}
+	}
+	formats = append(formats, f)
+}
+
+// LookupFormat returns the format registered under name
+func LookupFormat(name string) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	if alias, ok := formatAliases[name]; ok {
+		name = alias
+	}
+	for _, f := range formats {
+		if f.Name == name {
+			return *f, true
+		}
+	}
+	return Format{}, false
+}
+
+// FormatNames returns the names of the registered formats in the order
+// they were registered
+func FormatNames() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// DetectFormat picks the format for input by its file extension, then by
+// sniffing its content, falling back to "json". filename may be empty.
+// JSON has no extension of its own, so that a .json file holding JSON
+// Lines or a YAML document is still recognised.
+func DetectFormat(filename string, data []byte) string {
+	// Sniff functions may be registered by anyone, so they run without
+	// the lock held
+	formatsMu.RLock()
+	registered := append([]*Format(nil), formats...)
+	formatsMu.RUnlock()
+
+	if ext := filepath.Ext(filename); ext != "" {
+		for _, f := range registered {
+			for _, e := range f.Extensions {
+				if strings.EqualFold(e, ext) {
+					return f.Name
+				}
+			}
+		}
+	}
+	for _, f := range registered {
+		if f.Sniff != nil && f.Sniff(data) {
+			return f.Name
+		}
+	}
+	return "json"
+}
+
+// NewFromFormat creates a new JSON viewer from data in a registered format
+func NewFromFormat(name string, data []byte, config ...Config) (Model, error) {
+	f, ok := LookupFormat(name)
+	if !ok {
+		return Model{}, fmt.Errorf("unknown format %q", name)
+	}
+	if d, ok := f.Decoder.(modelDecoder); ok {
+		return d(data, config...)
+	}
+
+	cfg := DefaultConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	root, err := f.Decoder.Decode(data, cfg)
+	if root != nil {
+		root.Key = ""
+		root.Parent = nil
+		finishTree(root)
+		root.rebase("$")
+	}
+	return newFromParsed(root, err, config...)
+}
+
+// finishTree links the subtree under n to its parents and fills in the
+// values of objects and arrays from their children, as used by JSONPath
+// queries. Byte strings given as []byte or string become Bytes; any other
+// value in a byte string node becomes a null carrying an error, as the
+// rest of the viewer takes byte string values to be Bytes.
+func finishTree(n *Node) interface{} {
+	switch n.Type {
+	case ObjectNode:
+		value := make(map[string]interface{}, len(n.Children))
+		for _, child := range n.Children {
+			child.Parent = n
+			value[child.Key] = finishTree(child)
+		}
+		n.Value = value
+	case ArrayNode:
+		value := make([]interface{}, 0, len(n.Children))
+		for i, child := range n.Children {
+			child.Parent = n
+			if child.Key == "" {
+				child.Key = fmt.Sprintf("[%d]", i)
+			}
+			value = append(value, finishTree(child))
+		}
+		n.Value = value
+	case BytesNode:
+		switch v := n.Value.(type) {
+		case Bytes:
+		case []byte:
+			n.Value = Bytes(v)
+		case string:
+			n.Value = Bytes(v)
+		default:
+			n.Type = NullNode
+			n.Value = nil
+			n.Err = fmt.Errorf("byte string holds %T, not []byte", v)
+		}
+	}
+	return n.Value
+}
+
+// modelDecoder is a built-in format's NewFrom function. These set up more
+// than the tree, such as the line count that following JSON Lines relies
+// on, so NewFromFormat calls them directly.
+type modelDecoder func(data []byte, config ...Config) (Model, error)
+
+// Decode implements Decoder
+func (d modelDecoder) Decode(data []byte, config Config) (*Node, error) {
+	config.Lenient = true
+	m, err := d(data, config)
+	if err != nil {
+		return nil, err
+	}
+	return m.source, m.loadErr
+}
+
+func init() {
+	fromReader := func(newFrom func(r io.Reader, config ...Config) (Model, error)) modelDecoder {
+		return func(data []byte, config ...Config) (Model, error) {
+			return newFrom(bytes.NewReader(data), config...)
+		}
+	}
+	withConfig := func(newFrom modelDecoder, set func(*Config)) modelDecoder {
+		return func(data []byte, config ...Config) (Model, error) {
+			cfg := DefaultConfig()
+			if len(config) > 0 {
+				cfg = config[0]
+			}
+			set(&cfg)
+			return newFrom(data, cfg)
+		}
+	}
+	csv := fromReader(NewFromCSV)
+
+	RegisterFormat("json", nil, modelDecoder(NewFromJSON))
+	RegisterFormat("jsonl", looksLikeJSONLines, fromReader(NewFromJSONLines), ".jsonl", ".ndjson")
+	RegisterFormat("json5", nil, modelDecoder(NewFromJSON5), ".json5", ".jsonc")
+	RegisterFormat("stream", nil, withConfig(fromReader(NewFromReader), func(c *Config) { c.MultiDocument = true }))
+	RegisterFormat("yaml", looksLikeYAML, modelDecoder(NewFromYAML), ".yaml", ".yml")
+	RegisterFormat("toml", nil, modelDecoder(NewFromTOML), ".toml")
+	RegisterFormat("csv", nil, csv, ".csv")
+	RegisterFormat("tsv", nil, withConfig(csv, func(c *Config) { c.CSVDelimiter = '\t' }), ".tsv", ".tab")
+	RegisterFormat("xml", looksLikeXML, modelDecoder(NewFromXML), ".xml")
+	RegisterFormat("msgpack", nil, modelDecoder(NewFromMessagePack), ".msgpack", ".mpk")
+	RegisterFormat("cbor", looksLikeCBOR, modelDecoder(NewFromCBOR), ".cbor")
+}
+
+// looksLikeJSONLines reports whether data is not a single JSON document but
+// its first line is, which is how JSON Lines input presents itself.
+func looksLikeJSONLines(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	newline := bytes.IndexByte(trimmed, '\n')
+	if newline < 0 || json.Valid(trimmed) {
+		return false
+	}
+	return json.Valid(trimmed[:newline])
+}
+
+// looksLikeYAML reports whether data opens with a YAML document marker or
+// directive, which JSON never does
+func looksLikeYAML(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return bytes.HasPrefix(trimmed, []byte("---")) || bytes.HasPrefix(trimmed, []byte("%YAML"))
+}
+
+// looksLikeXML reports whether data opens with a tag, declaration or
+// comment, after any byte order mark
+func looksLikeXML(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n\xef\xbb\xbf"), []byte("<"))
+}
+
+// looksLikeCBOR reports whether data opens with the self-described CBOR
+// tag that may mark a CBOR file
+func looksLikeCBOR(data []byte) bool {
+	return bytes.HasPrefix(data, []byte{0xd9, 0xd9, 0xf7})
+}
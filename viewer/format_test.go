@@ -0,0 +1,129 @@
+package viewer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNewFromFormatAcceptsPlainByteSlices(t *testing.T) {
+	RegisterFormat("test-bytes", nil, DecoderFunc(func(data []byte, config Config) (*Node, error) {
+		return &Node{Type: ObjectNode, Children: []*Node{
+			{Key: "payload", Type: BytesNode, Value: []byte{0xde, 0xad, 0xbe, 0xef}},
+		}}, nil
+	}))
+
+	cfg := DefaultConfig()
+	cfg.InitiallyExpanded = true
+	m, err := NewFromFormat("test-bytes", nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := m.source.Children[0]
+	if _, ok := payload.Value.(Bytes); !ok {
+		t.Fatalf("payload value is %T, want Bytes", payload.Value)
+	}
+	if view := m.View(); !strings.Contains(view, "payload") {
+		t.Errorf("view does not show the payload:\n%s", view)
+	}
+}
+
+func TestNewFromFormatRejectsOtherByteStringValues(t *testing.T) {
+	RegisterFormat("test-bad-bytes", nil, DecoderFunc(func(data []byte, config Config) (*Node, error) {
+		return &Node{Type: ArrayNode, Children: []*Node{
+			{Type: BytesNode, Value: "text"},
+			{Type: BytesNode, Value: 42},
+			{Type: BytesNode},
+		}}, nil
+	}))
+
+	cfg := DefaultConfig()
+	cfg.InitiallyExpanded = true
+	m, err := NewFromFormat("test-bad-bytes", nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump := dumpTree(m.source); dump != `[b'74657874',null,null]` {
+		t.Errorf("tree = %s", dump)
+	}
+	for _, child := range m.source.Children[1:] {
+		if child.Err == nil {
+			t.Errorf("%s: no error for a byte string without bytes", child.Path)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "not []byte") {
+		t.Errorf("view does not show the error:\n%s", view)
+	}
+}
+
+func TestDetectFormatSniffsWithoutTheLock(t *testing.T) {
+	marker := []byte("register while sniffing")
+	RegisterFormat("test-sniff-registers", func(data []byte) bool {
+		if !bytes.Equal(data, marker) {
+			return false
+		}
+		RegisterFormat("test-sniff-registered", nil, nil)
+		return true
+	}, nil)
+
+	if got := DetectFormat("", marker); got != "test-sniff-registers" {
+		t.Errorf("DetectFormat = %q, want test-sniff-registers", got)
+	}
+	if _, ok := LookupFormat("test-sniff-registered"); !ok {
+		t.Error("format registered while sniffing is missing")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		filename string
+		data     string
+		want     string
+	}{
+		{"data.yaml", `{"a": 1}`, "yaml"},
+		{"data.YML", `{"a": 1}`, "yaml"},
+		{"data.xml", "---\na: 1\n", "xml"},
+		{"events.ndjson", `{"a": 1}`, "jsonl"},
+		{"config.jsonc", `{"a": 1}`, "json5"},
+		{"data.json", `{"a": 1}`, "json"},
+		{"data.json", "{\"a\": 1}\n{\"a\": 2}\n", "jsonl"},
+		{"data.json", "---\na: 1\n", "yaml"},
+		{"notes.txt", "<a/>", "xml"},
+		{"", "%YAML 1.2\n---\na: 1\n", "yaml"},
+		{"", "\xef\xbb\xbf<?xml version=\"1.0\"?><a/>", "xml"},
+		{"", "\xd9\xd9\xf7\xa1\x61a\x01", "cbor"},
+		{"", "[1,\n2]", "json"},
+		{"", "", "json"},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.filename, []byte(tt.data)); got != tt.want {
+			t.Errorf("DetectFormat(%q, %q) = %q, want %q", tt.filename, tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAliases(t *testing.T) {
+	tests := []struct {
+		alias string
+		name  string
+		data  string
+		want  string
+	}{
+		{"jsonc", "json5", "{a: 1, // note\n}", `{"a":1}`},
+		{"yml", "yaml", "a: 1\n", `{"a":1}`},
+	}
+	for _, tt := range tests {
+		f, ok := LookupFormat(tt.alias)
+		if !ok || f.Name != tt.name {
+			t.Errorf("LookupFormat(%q) = %q, %v, want %q", tt.alias, f.Name, ok, tt.name)
+		}
+		m, err := NewFromFormat(tt.alias, []byte(tt.data))
+		if err != nil {
+			t.Errorf("NewFromFormat(%q): %v", tt.alias, err)
+			continue
+		}
+		if dump := dumpTree(m.source); dump != tt.want {
+			t.Errorf("NewFromFormat(%q) = %s, want %s", tt.alias, dump, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package viewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+)
+
+// Inspect opens a full-screen viewer on v, which may be any Go value, and
+// returns when the user quits. It uses the terminal even when stdout is
+// captured, as under go test, and prints v as indented JSON to stdout
+// when there is no terminal at all, as in CI.
+func Inspect(v any, config ...Config) error {
+	if isTerminal(os.Stdout) {
+		return InspectWriter(os.Stdout, v, config...)
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return InspectWriter(os.Stdout, v, config...)
+	}
+	defer tty.Close()
+	return InspectWriter(tty, v, config...)
+}
+
+// InspectWriter is like Inspect, drawing the viewer on w. When w is not a
+// terminal, v is printed to w as indented JSON instead.
+func InspectWriter(w io.Writer, v any, config ...Config) error {
+	out, ok := w.(*os.File)
+	if !ok || !isTerminal(out) {
+		return printValue(w, v)
+	}
+
+	// Styles are rendered for the terminal being drawn on, which need not
+	// be stdout
+	if out != os.Stdout {
+		defer lipgloss.SetDefaultRenderer(lipgloss.DefaultRenderer())
+		lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(out))
+	}
+
+	cfg := DefaultConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithOutput(out)}
+	switch {
+	case out != os.Stdout:
+		opts = append(opts, tea.WithInput(out))
+	case !isTerminal(os.Stdin):
+		opts = append(opts, tea.WithInputTTY())
+	}
+
+	_, err := tea.NewProgram(New(v, cfg), opts...).Run()
+	return err
+}
+
+// printValue writes v as indented JSON, with struct fields in declaration
+// order as the viewer shows them. NaN and infinities are written as
+// strings, as copying does.
+func printValue(w io.Writer, v any) error {
+	out, err := json.MarshalIndent(orderedValue(BuildTree(v, "", "$")), "", "  ")
+	if err != nil {
+		// Should the tree still not marshal, fmt prints v as it is
+		_, err = fmt.Fprintf(w, "%+v\n", v)
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -0,0 +1,70 @@
+package viewer
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"testing"
+)
+
+type inspected struct {
+	Zeta  string   `json:"zeta"`
+	Alpha int      `json:"alpha"`
+	Tags  []string `json:"tags,omitempty"`
+	Inner struct {
+		Y bool
+		X *int
+	} `json:"inner"`
+}
+
+func TestInspectWriterPrintsJSON(t *testing.T) {
+	var v inspected
+	v.Zeta, v.Alpha = "z", 1
+	v.Inner.Y = true
+	want := `{
+  "zeta": "z",
+  "alpha": 1,
+  "inner": {
+    "Y": true,
+    "X": null
+  }
+}
+`
+
+	var buf bytes.Buffer
+	if err := InspectWriter(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("printed\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	// A file that is not a terminal gets the same
+	f, err := os.CreateTemp(t.TempDir(), "inspect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := InspectWriter(f, &v); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(f.Name()); string(got) != want {
+		t.Errorf("wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestInspectWriterPrintsNonFiniteNumbersAsStrings(t *testing.T) {
+	v := struct {
+		Name  string
+		Ratio float64
+		Limit float32
+	}{"nan", math.NaN(), float32(math.Inf(-1))}
+
+	var buf bytes.Buffer
+	if err := InspectWriter(&buf, v); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"Name\": \"nan\",\n  \"Ratio\": \"NaN\",\n  \"Limit\": \"-Infinity\"\n}\n"; buf.String() != want {
+		t.Errorf("printed %q, want %q", buf.String(), want)
+	}
+}
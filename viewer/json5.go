@@ -0,0 +1,493 @@
+package viewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NewFromJSON5 creates a new JSON viewer from JSON5 or JSONC data, as used
+// by tsconfig, VS Code settings and devcontainer files. Comments are shown
+// as annotations on the nodes they precede.
+func NewFromJSON5(data []byte, config ...Config) (Model, error) {
+	root, err := parseJSON5(data, "", "$")
+	return newFromParsed(root, err, config...)
+}
+
+// parseJSON5 decodes a JSON5 document, which also covers JSONC: comments,
+// trailing commas, single-quoted strings, unquoted keys and hex numbers are
+// accepted. Comments are attached to the node that follows them.
+func parseJSON5(data []byte, key, path string) (*Node, error) {
+	lex := &json5Lexer{src: data}
+	d := &jsonDecoder{dec: lex, source: data}
+
+	node, err := d.decodeNode(key, path)
+	if err != nil {
+		return node, err
+	}
+
+	lex.skipSpace()
+	if lex.pos < len(data) {
+		node.Err = newParseErrorAt(data, int64(lex.pos), ErrTrailingData)
+		return node, node.Err
+	}
+	return node, nil
+}
+
+// syntaxError is a syntax error reported by one of the relaxed parsers
+type syntaxError struct {
+	msg    string
+	Offset int64 // offset of the offending byte
+}
+
+func (e *syntaxError) Error() string {
+	return e.msg
+}
+
+// json5 lexer states: what the lexer expects to read next
+const (
+	expectValue = iota
+	expectKey
+	expectColon
+	expectComma
+	expectEnd
+)
+
+// json5Lexer turns JSON5 source into the token stream produced by
+// json.Decoder, so that the same tree decoder can consume it
+type json5Lexer struct {
+	src    []byte
+	pos    int
+	state  int
+	stack  []byte // open delimiters
+	offset int64  // offset just past the last token
+
+	pending []string // comments seen since the last token
+	comment string   // comments preceding the last token
+}
+
+// Comment returns the comments that preceded the last token read
+func (l *json5Lexer) Comment() string {
+	return l.comment
+}
+
+// InputOffset returns the offset just past the last token read
+func (l *json5Lexer) InputOffset() int64 {
+	return l.offset
+}
+
+// More reports whether the current array or object has another element
+func (l *json5Lexer) More() bool {
+	pos, pending := l.pos, len(l.pending)
+	defer func() {
+		l.pos, l.pending = pos, l.pending[:pending]
+	}()
+
+	l.skipSpace()
+	if l.state == expectComma && l.pos < len(l.src) && l.src[l.pos] == ',' {
+		l.pos++
+		l.skipSpace()
+	}
+	return l.pos < len(l.src) && l.src[l.pos] != '}' && l.src[l.pos] != ']'
+}
+
+// Token returns the next token: a json.Delim, string, json.Number, bool or
+// nil. Commas and colons are consumed silently, as json.Decoder does.
+func (l *json5Lexer) Token() (json.Token, error) {
+	l.skipSpace()
+
+	switch l.state {
+	case expectComma:
+		if l.pos >= len(l.src) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if l.src[l.pos] == ',' {
+			l.pos++
+			l.skipSpace()
+			l.state = expectValue
+			if l.top() == '{' {
+				l.state = expectKey
+			}
+		} else if !l.atClose() {
+			return nil, l.errorf("expected ',' or '%c'", closing(l.top()))
+		}
+	case expectColon:
+		if l.pos >= len(l.src) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if l.src[l.pos] != ':' {
+			return nil, l.errorf("expected ':' after object key")
+		}
+		l.pos++
+		l.skipSpace()
+		l.state = expectValue
+	case expectEnd:
+		if l.pos >= len(l.src) {
+			return nil, io.EOF
+		}
+		return nil, l.errorf("unexpected data after top-level value")
+	}
+
+	l.comment = strings.Join(l.pending, " ")
+	l.pending = l.pending[:0]
+
+	if l.pos >= len(l.src) {
+		if len(l.stack) == 0 && l.state == expectValue {
+			return nil, io.EOF
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	tok, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+	l.offset = int64(l.pos)
+	return tok, nil
+}
+
+// next reads the token at the current position and advances the state
+func (l *json5Lexer) next() (json.Token, error) {
+	c := l.src[l.pos]
+
+	if c == '}' || c == ']' {
+		if len(l.stack) == 0 || closing(l.top()) != c || l.state == expectColon ||
+			(c == '}' && l.state == expectValue) {
+			return nil, l.errorf("invalid character '%c'", c)
+		}
+		l.pos++
+		l.stack = l.stack[:len(l.stack)-1]
+		l.valueDone()
+		return json.Delim(c), nil
+	}
+
+	if l.state == expectKey {
+		key, err := l.readKey()
+		if err != nil {
+			return nil, err
+		}
+		l.state = expectColon
+		return key, nil
+	}
+
+	switch {
+	case c == '{' || c == '[':
+		l.pos++
+		l.stack = append(l.stack, c)
+		l.state = expectValue
+		if c == '{' {
+			l.state = expectKey
+		}
+		return json.Delim(c), nil
+	case c == '"' || c == '\'':
+		s, err := l.readString()
+		if err != nil {
+			return nil, err
+		}
+		l.valueDone()
+		return s, nil
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		n, err := l.readNumber()
+		if err != nil {
+			return nil, err
+		}
+		l.valueDone()
+		return n, nil
+	}
+
+	if c == '/' {
+		return nil, l.errorf("unterminated comment")
+	}
+
+	start := l.pos
+	word := l.readIdent()
+	l.valueDone()
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	case "Infinity", "NaN":
+		return json.Number(word), nil
+	}
+	l.pos = start
+	return nil, l.errorf("invalid character '%c' looking for beginning of value", c)
+}
+
+// valueDone moves to the state following a complete value
+func (l *json5Lexer) valueDone() {
+	if len(l.stack) == 0 {
+		l.state = expectEnd
+	} else {
+		l.state = expectComma
+	}
+}
+
+func (l *json5Lexer) top() byte {
+	if len(l.stack) == 0 {
+		return 0
+	}
+	return l.stack[len(l.stack)-1]
+}
+
+func (l *json5Lexer) atClose() bool {
+	return l.pos < len(l.src) && l.src[l.pos] == closing(l.top())
+}
+
+func closing(open byte) byte {
+	if open == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+// skipSpace skips whitespace and comments, collecting the comments
+func (l *json5Lexer) skipSpace() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v':
+			l.pos++
+		case c == '/' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '/':
+			end := l.pos + 2
+			for end < len(l.src) && l.src[end] != '\n' {
+				end++
+			}
+			l.addComment(string(l.src[l.pos+2 : end]))
+			l.pos = end
+		case c == '/' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '*':
+			end := strings.Index(string(l.src[l.pos+2:]), "*/")
+			if end < 0 {
+				// Leave the unterminated comment for the caller to reject
+				return
+			}
+			l.addComment(string(l.src[l.pos+2 : l.pos+2+end]))
+			l.pos += end + 4
+		default:
+			if r, size := utf8.DecodeRune(l.src[l.pos:]); r == '\uFEFF' || unicode.Is(unicode.Zs, r) {
+				l.pos += size
+				continue
+			}
+			return
+		}
+	}
+}
+
+// addComment records a comment, flattened onto a single line
+func (l *json5Lexer) addComment(text string) {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > 0 {
+		l.pending = append(l.pending, strings.Join(lines, " "))
+	}
+}
+
+// readKey reads an object key, quoted or a bare identifier
+func (l *json5Lexer) readKey() (string, error) {
+	c := l.src[l.pos]
+	if c == '"' || c == '\'' {
+		return l.readString()
+	}
+	if c == '/' {
+		return "", l.errorf("unterminated comment")
+	}
+	key := l.readIdent()
+	if key == "" {
+		return "", l.errorf("invalid character '%c' looking for beginning of object key", c)
+	}
+	return key, nil
+}
+
+// readIdent reads an ECMAScript-style identifier name
+func (l *json5Lexer) readIdent() string {
+	start := l.pos
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRune(l.src[l.pos:])
+		if r == '_' || r == '$' || unicode.IsLetter(r) || (l.pos > start && (unicode.IsDigit(r) || unicode.Is(unicode.Mn, r))) {
+			l.pos += size
+			continue
+		}
+		break
+	}
+	return string(l.src[start:l.pos])
+}
+
+// readString reads a single- or double-quoted string with JSON5 escapes
+func (l *json5Lexer) readString() (string, error) {
+	quote := l.src[l.pos]
+	l.pos++
+
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == quote:
+			l.pos++
+			return b.String(), nil
+		case c == '\n' || c == '\r':
+			return "", l.errorf("invalid character in string literal")
+		case c == '\\':
+			if err := l.readEscape(&b); err != nil {
+				return "", err
+			}
+		default:
+			r, size := utf8.DecodeRune(l.src[l.pos:])
+			b.WriteRune(r)
+			l.pos += size
+		}
+	}
+	return "", io.ErrUnexpectedEOF
+}
+
+// readEscape reads a backslash escape sequence into b
+func (l *json5Lexer) readEscape(b *strings.Builder) error {
+	l.pos++
+	if l.pos >= len(l.src) {
+		return io.ErrUnexpectedEOF
+	}
+
+	c := l.src[l.pos]
+	l.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 'f':
+		b.WriteByte('\f')
+	case 'n':
+		b.WriteByte('\n')
+	case 'r':
+		b.WriteByte('\r')
+	case 't':
+		b.WriteByte('\t')
+	case 'v':
+		b.WriteByte('\v')
+	case '0':
+		b.WriteByte(0)
+	case '\n':
+		// Line continuation
+	case '\r':
+		if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+			l.pos++
+		}
+	case 'x':
+		r, err := l.readHex(2)
+		if err != nil {
+			return err
+		}
+		b.WriteRune(r)
+	case 'u':
+		r, err := l.readHex(4)
+		if err != nil {
+			return err
+		}
+		if utf16.IsSurrogate(r) && l.pos+1 < len(l.src) && l.src[l.pos] == '\\' && l.src[l.pos+1] == 'u' {
+			l.pos += 2
+			low, err := l.readHex(4)
+			if err != nil {
+				return err
+			}
+			r = utf16.DecodeRune(r, low)
+		}
+		b.WriteRune(r)
+	default:
+		l.pos--
+		r, size := utf8.DecodeRune(l.src[l.pos:])
+		if r >= '1' && r <= '9' {
+			return l.errorf("invalid character '%c' in string escape code", r)
+		}
+		b.WriteRune(r)
+		l.pos += size
+	}
+	return nil
+}
+
+// readHex reads n hex digits as a rune
+func (l *json5Lexer) readHex(n int) (rune, error) {
+	if l.pos+n > len(l.src) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	v, err := strconv.ParseUint(string(l.src[l.pos:l.pos+n]), 16, 32)
+	if err != nil {
+		return 0, l.errorf("invalid character in string escape code")
+	}
+	l.pos += n
+	return rune(v), nil
+}
+
+// readNumber reads a JSON5 number and normalizes it to a JSON literal:
+// hex is converted to decimal and bare leading or trailing points and a
+// leading '+' are dropped
+func (l *json5Lexer) readNumber() (json.Number, error) {
+	start := l.pos
+	sign := ""
+	if c := l.src[l.pos]; c == '+' || c == '-' {
+		if c == '-' {
+			sign = "-"
+		}
+		l.pos++
+	}
+
+	word := l.readNumberWord()
+	switch {
+	case word == "Infinity" || word == "NaN":
+		return json.Number(sign + word), nil
+	case strings.HasPrefix(word, "0x") || strings.HasPrefix(word, "0X"):
+		n, ok := new(big.Int).SetString(word[2:], 16)
+		if !ok {
+			l.pos = start
+			return "", l.errorf("invalid character in numeric literal")
+		}
+		if sign == "-" {
+			n.Neg(n)
+		}
+		return json.Number(n.String()), nil
+	}
+
+	lit := word
+	if strings.HasPrefix(lit, ".") {
+		lit = "0" + lit
+	}
+	if i := strings.Index(lit, "."); i >= 0 && (i == len(lit)-1 || lit[i+1] == 'e' || lit[i+1] == 'E') {
+		lit = lit[:i] + lit[i+1:]
+	}
+	lit = sign + lit
+
+	if !json.Valid([]byte(lit)) {
+		l.pos = start
+		return "", l.errorf("invalid character in numeric literal")
+	}
+	return json.Number(lit), nil
+}
+
+// readNumberWord reads the characters that can make up a number literal
+func (l *json5Lexer) readNumberWord() string {
+	start := l.pos
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		isExpSign := (c == '+' || c == '-') && l.pos > start && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E') &&
+			!strings.HasPrefix(strings.ToLower(string(l.src[start:l.pos])), "0x")
+		if c == '.' || c == '_' || isExpSign || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			l.pos++
+			continue
+		}
+		break
+	}
+	return string(l.src[start:l.pos])
+}
+
+// errorf creates a syntax error at the current position
+func (l *json5Lexer) errorf(format string, args ...interface{}) error {
+	return &syntaxError{msg: fmt.Sprintf(format, args...), Offset: int64(l.pos)}
+}
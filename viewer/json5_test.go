@@ -0,0 +1,128 @@
+package viewer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseJSON5(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"a": 1, "b": [true, null]}`, `{"a":1,"b":[true,null]}`},
+		{"// settings\n{a: 1, 'b': \"two\", \"c\": [1, 2,], }", `{"a":1,"b":"two","c":[1,2]}`},
+		{`{"a": 1, // trailing` + "\n}", `{"a":1}`},
+		{`{café: 1, $x_1: 2, _: 3}`, `{"café":1,"$x_1":2,"_":3}`},
+		{`{a: {b: [{}, []]}}`, `{"a":{"b":[{},[]]}}`},
+		{`[0x1F, 0XfF, -0xff, +0x10, 0x10000000000000000]`, `[31,255,-255,16,18446744073709551616]`},
+		{`[.5, 5., +1, 1.e3, -.5e-2, 1E+2, 0]`, `[0.5,5,1,1e3,-0.5e-2,1E+2,0]`},
+		{`[Infinity, -Infinity, +Infinity, NaN, -NaN]`, `[Infinity,-Infinity,Infinity,NaN,-NaN]`},
+		{`'it\'s'`, `"it's"`},
+		{`"say \"hi\""`, `"say \"hi\""`},
+		{`"\x41\u00e9\uD83D\uDE00"`, `"Aé😀"`},
+		{"'line \\\ncontinued'", `"line continued"`},
+		{"'crlf \\\r\ncontinued'", `"crlf continued"`},
+		{`"\b\f\n\r\t\v\0"`, `"\b\f\n\r\t\v\x00"`},
+		{`"\q\/\\"`, `"q/\\"`},
+		{"\uFEFF{\u00a0a:\u20001}", `{"a":1}`},
+		{"/* multi\n * line\n */ 1", "1"},
+		{"1 // done", "1"},
+	}
+	for _, tt := range tests {
+		root, err := parseJSON5([]byte(tt.input), "", "$")
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+			continue
+		}
+		if got := dumpTree(root); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseJSON5Comments(t *testing.T) {
+	root, err := parseJSON5([]byte(`// the config
+{
+  // the name
+  name: 'x', /* count
+   * of items */ n: 1,
+  list: [
+    1, // not this one
+    2,
+  ],
+}`), "", "$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"$":         "the config",
+		"$.name":    "the name",
+		"$.n":       "count of items",
+		"$.list[1]": "not this one",
+	}
+	var check func(n *Node)
+	check = func(n *Node) {
+		if n.Comment != want[n.Path] {
+			t.Errorf("%s: comment %q, want %q", n.Path, n.Comment, want[n.Path])
+		}
+		for _, child := range n.Children {
+			check(child)
+		}
+	}
+	check(root)
+}
+
+func TestParseJSON5Errors(t *testing.T) {
+	tests := []struct {
+		input  string
+		err    string
+		column int
+	}{
+		{``, "unexpected EOF", 1},
+		{`{a 1}`, "expected ':' after object key", 4},
+		{`[1 2]`, "expected ',' or ']'", 4},
+		{`{a: 1 b: 2}`, "expected ',' or '}'", 7},
+		{`{,}`, "invalid character ',' looking for beginning of object key", 2},
+		{`[,]`, "invalid character ',' looking for beginning of value", 2},
+		{`{a:}`, "invalid character '}'", 4},
+		{`{a:1]`, "expected ',' or '}'", 5},
+		{`tru`, "invalid character 't' looking for beginning of value", 1},
+		{`'abc`, "unexpected EOF", 5},
+		{"\"a\nb\"", "invalid character in string literal", 3},
+		{`"\1"`, "invalid character '1' in string escape code", 3},
+		{`"\uZZZZ"`, "invalid character in string escape code", 4},
+		{`"\u12"`, "unexpected EOF", 7},
+		{`0x`, "invalid character in numeric literal", 1},
+		{`0xG`, "invalid character in numeric literal", 1},
+		{`1..2`, "invalid character in numeric literal", 1},
+		{`+`, "invalid character in numeric literal", 1},
+		{`[1, /* never closed`, "unterminated comment", 5},
+		{`{/* never closed`, "unterminated comment", 2},
+		{`[1,2`, "unexpected EOF", 5},
+		{`{a:1} x`, "unexpected data after top-level value", 7},
+	}
+	for _, tt := range tests {
+		_, err := parseJSON5([]byte(tt.input), "", "$")
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: error %v, want a ParseError", tt.input, err)
+			continue
+		}
+		if !strings.Contains(parseErr.Err.Error(), tt.err) || parseErr.Column != tt.column {
+			t.Errorf("%q: %v, want %q at column %d", tt.input, err, tt.err, tt.column)
+		}
+	}
+}
+
+func TestParseJSON5KeepsPartialTree(t *testing.T) {
+	root, err := parseJSON5([]byte(`{a: 1, b: [2, 3,`), "", "$")
+	if err == nil || root == nil {
+		t.Fatalf("got %v, %v, want a partial tree and an error", root, err)
+	}
+	if got := dumpTree(root); got != `{"a":1,"b":[2,3]}` {
+		t.Errorf("partial tree %s", got)
+	}
+}
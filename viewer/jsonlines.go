@@ -0,0 +1,145 @@
+package viewer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// NewFromJSONLines creates a new JSON viewer from newline-delimited JSON
+// (JSON Lines / NDJSON). Every record becomes a top-level node keyed by its
+// line number; lines that fail to parse are kept as error nodes holding the
+// raw text instead of aborting the load. Further records can be appended
+// by sending the model a LinesMsg.
+func NewFromJSONLines(reader io.Reader, config ...Config) (Model, error) {
+	root, lines, err := buildLinesTree(reader)
+	if err != nil {
+		return Model{}, err
+	}
+	m := newModel(root, root.Value, config...)
+	m.lineCount = lines
+	return m, nil
+}
+
+// LinesMsg appends JSON Lines records to a viewer created by
+// NewFromJSONLines, e.g. as a followed log file grows. Each entry is one
+// complete line; blank lines are counted but not shown.
+type LinesMsg struct {
+	Lines [][]byte
+}
+
+// buildLinesTree reads JSON Lines from reader and builds an array node with
+// one child per non-blank line, returning the number of lines read. Only
+// read errors are returned.
+func buildLinesTree(reader io.Reader) (*Node, int, error) {
+	root := &Node{Type: ArrayNode, Path: "$", Value: make([]interface{}, 0)}
+
+	br := bufio.NewReader(reader)
+	lines := 0
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			lines++
+			appendRecord(root, line, lines)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return root, lines, nil
+}
+
+// appendRecord parses line as record lineNo and appends it to root,
+// returning the new child, or nil for a blank line
+func appendRecord(root *Node, line []byte, lineNo int) *Node {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil
+	}
+
+	records, _ := root.Value.([]interface{})
+	child, value := buildLineNode(line, lineNo, len(records))
+	child.Parent = root
+	root.Children = append(root.Children, child)
+	root.Value = append(records, value)
+	return child
+}
+
+// appendLines adds records to the source and the displayed tree, keeping
+// the cursor, expansion state and any active filter
+func (m *Model) appendLines(lines [][]byte) {
+	if m.source.Type != ArrayNode {
+		return
+	}
+
+	var added []*Node
+	for _, line := range lines {
+		m.lineCount++
+		if child := appendRecord(m.source, line, m.lineCount); child != nil {
+			if m.config.ExpandEmbeddedJSON || m.config.DecodeBase64 {
+				if expandStrings(child, m.config) {
+					updateChildValue(m.source, len(m.source.Children)-1)
+				}
+			}
+			m.index.add(child)
+			m.nodeCount += CountNodes(child)
+			added = append(added, child)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	m.index.put(m.source)
+	m.rawData = m.source.Value
+
+	if m.isJSONPathFilter() {
+		m.rebuildTree()
+		return
+	}
+
+	// The displayed tree mirrors the source, so new records go at the end
+	current := m.GetCurrentNode()
+	for _, child := range added {
+		c := child.Clone()
+		c.Parent = m.root
+		m.root.Children = append(m.root.Children, c)
+	}
+	m.root.Value = m.rawData
+
+	m.updateViewNodes()
+	for i, node := range m.viewNodes {
+		if node == current {
+			m.cursor = i
+			break
+		}
+	}
+	m.updateViewport()
+}
+
+// buildLineNode parses a single JSON Lines record. A malformed record is
+// returned as a string node carrying the parse error.
+func buildLineNode(line []byte, lineNo, index int) (*Node, interface{}) {
+	key := fmt.Sprintf("%d", lineNo)
+	path := fmt.Sprintf("$[%d]", index)
+
+	node, err := parseJSON(line, key, path)
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.lineBase = lineNo - 1
+			perr.Line += perr.lineBase
+		}
+		raw := string(bytes.TrimSpace(line))
+		return &Node{
+			Key:   key,
+			Value: raw,
+			Type:  StringNode,
+			Path:  path,
+			Err:   err,
+		}, raw
+	}
+	return node, node.Value
+}
@@ -0,0 +1,135 @@
+package viewer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/scanner"
+	"time"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// jsonpathLanguage is the JSONPath language with equality operators that
+// understand json.Number, so lossless numbers still match number literals.
+// Number literals that float64 cannot hold are kept as json.Number so that
+// they compare exactly. Operators listed before the base language take
+// precedence when merged, while prefixes such as literals take precedence
+// when listed after it.
+// Number operators and the time and now functions allow filtering by time,
+// as in $[?(time(@.created) > now() - 86400)].
+var jsonpathLanguage = gval.NewLanguage(
+	gval.InfixOperator("==", func(a, b interface{}) (interface{}, error) {
+		return valuesEqual(a, b), nil
+	}),
+	gval.InfixOperator("!=", func(a, b interface{}) (interface{}, error) {
+		return !valuesEqual(a, b), nil
+	}),
+	compareOperator("<", func(a, b float64) bool { return a < b }),
+	compareOperator("<=", func(a, b float64) bool { return a <= b }),
+	compareOperator(">", func(a, b float64) bool { return a > b }),
+	compareOperator(">=", func(a, b float64) bool { return a >= b }),
+	arithmeticOperator("+", func(a, b float64) float64 { return a + b }),
+	arithmeticOperator("-", func(a, b float64) float64 { return a - b }),
+	arithmeticOperator("*", func(a, b float64) float64 { return a * b }),
+	arithmeticOperator("/", func(a, b float64) float64 { return a / b }),
+	gval.Function("time", timeFunction),
+	gval.Function("now", func() float64 {
+		return unixSeconds(time.Now())
+	}),
+	jsonpath.Language(),
+	gval.PrefixExtension(scanner.Int, numberLiteral),
+	gval.PrefixExtension(scanner.Float, numberLiteral),
+	gval.PrefixOperator("-", func(c context.Context, v interface{}) (interface{}, error) {
+		if n, ok := v.(json.Number); ok {
+			return negateNumber(n), nil
+		}
+		f, ok := plainNumber(v).(float64)
+		if !ok {
+			return nil, fmt.Errorf("- needs a number, got %v", v)
+		}
+		return -f, nil
+	}),
+)
+
+// numberLiteral parses a number constant in an expression. Constants that
+// float64 holds exactly stay float64, which array indexes and slices need.
+func numberLiteral(c context.Context, p *gval.Parser) (gval.Evaluable, error) {
+	n := json.Number(p.TokenText())
+	if isInexactNumber(n) {
+		return p.Const(n), nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return nil, err
+	}
+	return p.Const(f), nil
+}
+
+// negateNumber negates a number literal without losing precision
+func negateNumber(n json.Number) json.Number {
+	if strings.HasPrefix(string(n), "-") {
+		return n[1:]
+	}
+	return "-" + n
+}
+
+// compareOperator is an ordering operator on numbers, including
+// json.Number, which compare exactly. Comparing anything else, such as a
+// missing field, is false.
+func compareOperator(name string, cmp func(a, b float64) bool) gval.Language {
+	return gval.InfixOperator(name, func(a, b interface{}) (interface{}, error) {
+		if x, ok := exactNumber(a); ok {
+			if y, ok := exactNumber(b); ok {
+				return cmp(float64(x.Cmp(y)), 0), nil
+			}
+		}
+		x, okx := plainNumber(a).(float64)
+		y, oky := plainNumber(b).(float64)
+		return okx && oky && cmp(x, y), nil
+	})
+}
+
+// arithmeticOperator is an arithmetic operator on numbers, including
+// json.Number
+func arithmeticOperator(name string, op func(a, b float64) float64) gval.Language {
+	return gval.InfixOperator(name, func(a, b interface{}) (interface{}, error) {
+		x, okx := plainNumber(a).(float64)
+		y, oky := plainNumber(b).(float64)
+		if !okx || !oky {
+			return nil, fmt.Errorf("%s needs numbers, got %v and %v", name, a, b)
+		}
+		return op(x, y), nil
+	})
+}
+
+// timeFunction is the JSONPath time function, which gives the seconds since
+// the epoch of an epoch timestamp, an RFC 3339 string or a date such as
+// "2024-06-10". Values that are not times give null, which never matches.
+func timeFunction(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		if t, err := time.Parse(time.DateOnly, s); err == nil {
+			return unixSeconds(t)
+		}
+	}
+	if t, ok := valueTime(v); ok {
+		return unixSeconds(t)
+	}
+	return nil
+}
+
+// unixSeconds returns t as fractional seconds since the epoch
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
+
+// queryJSONPath evaluates a JSONPath expression against data
+func queryJSONPath(path string, data interface{}) (interface{}, error) {
+	eval, err := jsonpathLanguage.NewEvaluable(path)
+	if err != nil {
+		return nil, err
+	}
+	return eval(context.Background(), data)
+}
@@ -0,0 +1,195 @@
+package viewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryJSONPathNumbers(t *testing.T) {
+	root, err := parseJSON([]byte(`[
+		{"id": 9007199254740992},
+		{"id": 9007199254740993},
+		{"id": 0.1},
+		{"id": -12345678901234567890},
+		{"id": 5}
+	]`), "", "$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []json.Number
+	}{
+		{`$[?(@.id == 9007199254740992)].id`, []json.Number{"9007199254740992"}},
+		{`$[?(@.id == 9007199254740993)].id`, []json.Number{"9007199254740993"}},
+		{`$[?(@.id != 9007199254740993)].id`, []json.Number{"9007199254740992", "0.1", "-12345678901234567890", "5"}},
+		{`$[?(@.id > 9007199254740992)].id`, []json.Number{"9007199254740993"}},
+		{`$[?(@.id == 0.1)].id`, []json.Number{"0.1"}},
+		{`$[?(@.id == -12345678901234567890)].id`, []json.Number{"-12345678901234567890"}},
+		{`$[?(@.id == 5)].id`, []json.Number{"5"}},
+		{`$[?(@.id == 2 + 3)].id`, []json.Number{"5"}},
+		{`$[4].id`, []json.Number{"5"}},
+	}
+	for _, tt := range tests {
+		got, err := queryJSONPath(tt.query, root.Value)
+		if err != nil {
+			t.Errorf("%s: %v", tt.query, err)
+			continue
+		}
+		var numbers []json.Number
+		switch v := got.(type) {
+		case []interface{}:
+			for _, n := range v {
+				numbers = append(numbers, n.(json.Number))
+			}
+		case json.Number:
+			numbers = []json.Number{v}
+		}
+		if !reflect.DeepEqual(numbers, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.query, numbers, tt.want)
+		}
+	}
+}
+
+func TestQueryJSONPathTimes(t *testing.T) {
+	root, err := parseJSON([]byte(`[
+		{"id": 1, "created": 1718000000, "n": 2},
+		{"id": 2, "created": "2024-06-11T00:00:00Z", "n": 5},
+		{"id": 3, "created": "2024-06-09", "n": 3},
+		{"id": 4, "created": "soon", "n": 4}
+	]`), "", "$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []json.Number
+	}{
+		{`$[?(time(@.created) > time("2024-06-10"))].id`, []json.Number{"1", "2"}},
+		{`$[?(time(@.created) < 1717977600)].id`, []json.Number{"3"}},
+		{`$[?(time(@.created) < now())].id`, []json.Number{"1", "2", "3"}},
+		{`$[?(time(@.created) > now() - 86400)].id`, nil},
+		{`$[?(@.created > 1717999999)].id`, []json.Number{"1"}},
+		{`$[?(@.n < 3)].id`, []json.Number{"1"}},
+		{`$[?(@.n + 1 > 5)].id`, []json.Number{"2"}},
+		{`$[?(@.n * 2 == 8)].id`, []json.Number{"4"}},
+	}
+	for _, tt := range tests {
+		got, err := queryJSONPath(tt.query, root.Value)
+		if err != nil {
+			t.Errorf("%s: %v", tt.query, err)
+			continue
+		}
+		var ids []json.Number
+		for _, id := range got.([]interface{}) {
+			ids = append(ids, id.(json.Number))
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.query, ids, tt.want)
+		}
+	}
+}
+
+func TestEpochTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		ok   bool
+	}{
+		{"1718000000", time.Unix(1718000000, 0), true},
+		{"1718000000.25", time.Unix(1718000000, 250000000), true},
+		{"1718000000123", time.UnixMilli(1718000000123), true},
+		{"1718000000123456", time.UnixMicro(1718000000123456), true},
+		{"1718000000123456789", time.Unix(0, 1718000000123456789), true},
+		{"1718000000123.5", time.Time{}, false},
+		{"1718000000123456.5", time.Time{}, false},
+		{"171800000", time.Time{}, false},
+		{"17180000001", time.Time{}, false},
+		{"0718000000", time.Time{}, false},
+		{"-1718000000", time.Time{}, false},
+		{".5", time.Time{}, false},
+		{"42", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := epochTime(tt.in)
+		if ok != tt.ok || (ok && !got.Equal(tt.want)) {
+			t.Errorf("epochTime(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "just now"},
+		{999 * time.Millisecond, "just now"},
+		{-999 * time.Millisecond, "just now"},
+		{time.Second, "1s ago"},
+		{59 * time.Second, "59s ago"},
+		{time.Minute, "1m ago"},
+		{59 * time.Minute, "59m ago"},
+		{time.Hour, "1h ago"},
+		{day - time.Minute, "23h ago"},
+		{day, "1d ago"},
+		{29 * day, "29d ago"},
+		{30 * day, "1mo ago"},
+		{364 * day, "12mo ago"},
+		{365 * day, "1y ago"},
+		{-time.Second, "in 1s"},
+		{-2 * time.Hour, "in 2h"},
+		{-400 * day, "in 1y"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.d); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestSortByTime(t *testing.T) {
+	m, err := NewFromJSON([]byte(`[
+		{"id": 1, "at": "2024-06-10T00:00:00Z"},
+		{"id": 2},
+		{"id": 3, "at": 1700000000},
+		{"id": 4, "at": "2024-01-01T00:00:00Z"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.root.ExpandAll()
+	m.updateViewNodes()
+	for i, n := range m.viewNodes {
+		if n.Path == "$[0].at" {
+			m.cursor = i
+		}
+	}
+
+	ids := func() []string {
+		var out []string
+		for _, child := range m.root.Children {
+			out = append(out, fmt.Sprint(child.Children[0].Value))
+		}
+		return out
+	}
+
+	m.sortByTime()
+	if got, want := ids(), []string{"3", "4", "1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first sort = %v, want %v", got, want)
+	}
+	if m.viewNodes[m.cursor].Path != "$[0].at" {
+		t.Errorf("cursor moved to %s", m.viewNodes[m.cursor].Path)
+	}
+
+	m.sortByTime()
+	if got, want := ids(), []string{"1", "4", "3", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second sort = %v, want %v", got, want)
+	}
+}
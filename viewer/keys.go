@@ -93,6 +93,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("?"),
 			key.WithHelp("?", "toggle help"),
 		),
+		Reload: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reload source"),
+		),
+		BytesFormat: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "bytes as hex/base64"),
+		),
+		SortByTime: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort list by time"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c", "esc"),
 			key.WithHelp("q/esc", "quit"),
@@ -112,8 +124,8 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.PageUp, k.PageDown, k.Home, k.End},
 		{k.Enter, k.ExpandAll, k.CollapseAll},
 		{k.Filter, k.JSONPath, k.Search, k.Goto},
-		{k.Copy, k.CopyPath, k.CopyKey},
-		{k.NextMatch, k.PrevMatch, k.Reset},
+		{k.Copy, k.CopyPath, k.CopyKey, k.BytesFormat, k.SortByTime},
+		{k.NextMatch, k.PrevMatch, k.Reset, k.Reload},
 		{k.Help, k.Quit},
 	}
 }
\ No newline at end of file
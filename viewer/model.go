@@ -1,7 +1,6 @@
 package viewer
 
 import (
-	"encoding/json"
 	"io"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -12,14 +11,27 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// New creates a new JSON viewer model
+// New creates a new JSON viewer model for decoded JSON or any other Go
+// value, such as a struct or a slice of them
 func New(data interface{}, config ...Config) Model {
+	root := BuildTree(data, "", "$")
+	return newModel(root, root.Value, config...)
+}
+
+// newModel creates a viewer for an already built source tree. The source
+// tree is kept pristine so that resetting the view can restore it.
+func newModel(source *Node, data interface{}, config ...Config) Model {
 	cfg := DefaultConfig()
 	if len(config) > 0 {
 		cfg = config[0]
 	}
 
-	root := BuildTree(data, "", "$")
+	if cfg.ExpandEmbeddedJSON || cfg.DecodeBase64 {
+		expandStrings(source, cfg)
+		data = source.Value
+	}
+
+	root := source.Clone()
 	if cfg.InitiallyExpanded {
 		root.Expanded = true
 	}
@@ -34,6 +46,8 @@ func New(data interface{}, config ...Config) Model {
 
 	m := Model{
 		root:      root,
+		source:    source,
+		index:     indexTree(source),
 		rawData:   data,
 		config:    cfg,
 		cursor:    0,
@@ -53,22 +67,67 @@ func New(data interface{}, config ...Config) Model {
 	return m
 }
 
-// NewFromJSON creates a new JSON viewer from JSON data
+// NewFromJSON creates a new JSON viewer from JSON data, keeping object
+// members in their source order. With Config.Lenient, a malformed document
+// is loaded up to the error, which is then reported by LoadError.
 func NewFromJSON(jsonData []byte, config ...Config) (Model, error) {
-	var data interface{}
-	if err := json.Unmarshal(jsonData, &data); err != nil {
+	root, err := parseJSON(jsonData, "", "$")
+	return newFromParsed(root, err, config...)
+}
+
+// newFromParsed creates a viewer for the result of a parser, which may be a
+// partial tree alongside a parse error
+func newFromParsed(root *Node, err error, config ...Config) (Model, error) {
+	lenient := len(config) > 0 && config[0].Lenient
+	if err != nil && (root == nil || !lenient) {
 		return Model{}, err
 	}
-	return New(data, config...), nil
+
+	m := newModel(root, root.Value, config...)
+	if err != nil {
+		m.loadErr = err
+		if node := findErrorNode(m.root); node != nil {
+			node.Expanded = true
+			m.revealNode(node)
+		}
+	}
+	return m, nil
 }
 
-// NewFromReader creates a new JSON viewer from an io.Reader
+// NewFromReader creates a new JSON viewer from an io.Reader. gzip and
+// bzip2 input is recognised by its magic bytes and decompressed, with both
+// sizes shown in the header. With Config.MultiDocument, the reader is
+// decoded as a stream of concatenated top-level values shown as sibling
+// documents $[0], $[1], ...
 func NewFromReader(reader io.Reader, config ...Config) (Model, error) {
-	data, err := io.ReadAll(reader)
+	compressed := &countingReader{r: reader}
+	decompressed, compression, err := Decompress(compressed)
 	if err != nil {
 		return Model{}, err
 	}
-	return NewFromJSON(data, config...)
+	counted := &countingReader{r: decompressed}
+
+	var m Model
+	if len(config) > 0 && config[0].MultiDocument {
+		root, err := parseJSONStream(counted)
+		if m, err = newFromParsed(root, err, config...); err != nil {
+			return Model{}, err
+		}
+	} else {
+		data, err := io.ReadAll(counted)
+		if err != nil {
+			return Model{}, err
+		}
+		if m, err = NewFromJSON(data, config...); err != nil {
+			return Model{}, err
+		}
+	}
+
+	if compression != "" {
+		m.fileSize = counted.n
+		m = m.WithCompression(compression, compressed.n)
+	}
+	return m, nil
 }
 
 // WithFilename sets the filename for display purposes
@@ -78,6 +137,35 @@ func (m Model) WithFilename(filename string, size int64) Model {
 	return m
 }
 
+// WithStatus shows a message in the footer, such as a warning about how
+// the data was obtained. It stays until replaced by a later status.
+func (m Model) WithStatus(message string, isErr bool) Model {
+	m.setStatus(message, isErr)
+	m.statusSticky = true
+	return m
+}
+
+// WithTabs shows a tab bar in the header for a viewer that is one of
+// several, e.g. one per file, with the given tab marked as active
+func (m Model) WithTabs(names []string, active int) Model {
+	m.tabs = names
+	m.activeTab = active
+	return m
+}
+
+// WithCompression records that the data was decompressed from the given
+// format and compressed size, which the header shows next to the size
+func (m Model) WithCompression(format string, size int64) Model {
+	m.compression = format
+	m.compressedSize = size
+	return m
+}
+
+// LoadError returns the error that cut a lenient load short, if any
+func (m Model) LoadError() error {
+	return m.loadErr
+}
+
 // GetCurrentNode returns the currently selected node
 func (m Model) GetCurrentNode() *Node {
 	if m.cursor >= 0 && m.cursor < len(m.viewNodes) {
@@ -95,6 +183,12 @@ func (m Model) GetFilteredData() interface{} {
 	return m.root.Value
 }
 
+// IsInputActive returns true while a filter, query, search or goto path
+// is being typed, when keys go to the input rather than to navigation
+func (m Model) IsInputActive() bool {
+	return m.filterMode || m.jsonpathMode || m.searchMode || m.gotoMode
+}
+
 // IsFiltered returns true if any filter is currently active
 func (m Model) IsFiltered() bool {
 	return m.filter != ""
@@ -146,6 +240,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
+
+	case LinesMsg:
+		m.appendLines(msg.Lines)
+		return m, nil
+
+	case ReloadMsg:
+		return m, m.handleReload(msg)
+
+	case clearChangesMsg:
+		if msg.gen == m.changeGen {
+			m.changes = nil
+			m.removed = nil
+			m.rebuildTree()
+		}
+		return m, nil
 	}
 
 	m.viewport, cmd = m.viewport.Update(msg)
@@ -154,6 +263,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyPress handles key press events
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.statusSticky {
+		m.status = ""
+	}
+
 	// Handle input modes first
 	if m.filterMode || m.jsonpathMode || m.searchMode || m.gotoMode {
 		return m.handleInputMode(msg)
@@ -190,6 +303,12 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.collapseAll()
 	case key.Matches(msg, m.keys.Reset):
 		m.resetView()
+	case key.Matches(msg, m.keys.Reload):
+		return m, m.reloadCmd()
+	case key.Matches(msg, m.keys.BytesFormat):
+		m.toggleBytesFormat()
+	case key.Matches(msg, m.keys.SortByTime):
+		m.sortByTime()
 	case key.Matches(msg, m.keys.Filter):
 		m.enterFilterMode()
 	case key.Matches(msg, m.keys.JSONPath):
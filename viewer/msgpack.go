@@ -0,0 +1,214 @@
+package viewer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// NewFromMessagePack creates a new JSON viewer from MessagePack data.
+// Maps keep their entry order, binary values become byte string nodes,
+// timestamps become datetime nodes, and other extension values are shown
+// as byte strings annotated with their extension type. Several values one
+// after another are shown as sibling documents $[0], $[1], ...
+func NewFromMessagePack(data []byte, config ...Config) (Model, error) {
+	root, err := parseMessagePack(data)
+	return newFromParsed(root, err, config...)
+}
+
+// parseMessagePack decodes every value in data into a Node tree
+func parseMessagePack(data []byte) (*Node, error) {
+	d := &msgpackDecoder{binaryReader{data: data}}
+	return decodeBinarySequence(&d.binaryReader, d.decode)
+}
+
+// msgpackTimestamp is the extension type of MessagePack timestamps
+const msgpackTimestamp = -1
+
+// msgpackDecoder builds Node trees from MessagePack values
+type msgpackDecoder struct {
+	binaryReader
+}
+
+// decode reads the next value
+func (d *msgpackDecoder) decode(key, path string) (*Node, error) {
+	d.start = d.pos
+	b, err := d.readByte()
+	if err != nil {
+		return nil, d.fail(err)
+	}
+
+	switch {
+	case b <= 0x7f:
+		return d.number(key, path, int64(b))
+	case b >= 0xe0:
+		return d.number(key, path, int64(int8(b)))
+	case b&0xf0 == 0x80:
+		return d.mapValue(key, path, uint64(b&0x0f))
+	case b&0xf0 == 0x90:
+		return d.array(key, path, uint64(b&0x0f))
+	case b&0xe0 == 0xa0:
+		return d.str(key, path, uint64(b&0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return BuildTree(nil, key, path), nil
+	case 0xc2:
+		return BuildTree(false, key, path), nil
+	case 0xc3:
+		return BuildTree(true, key, path), nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (b - 0xc4))
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		data, err := d.next(n)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return d.bytes(key, path, data), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (b - 0xc7))
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return d.ext(key, path, n)
+	case 0xca:
+		bits, err := d.uint(4)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return BuildTree(floatNumber(float64(math.Float32frombits(uint32(bits))), 32), key, path), nil
+	case 0xcb:
+		bits, err := d.uint(8)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return BuildTree(floatNumber(math.Float64frombits(bits), 64), key, path), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := d.uint(1 << (b - 0xcc))
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return BuildTree(json.Number(strconv.FormatUint(n, 10)), key, path), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		n, err := d.uint(size)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		// Sign-extend from the integer's width
+		shift := 64 - 8*size
+		return d.number(key, path, int64(n<<shift)>>shift)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(key, path, 1<<(b-0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (b - 0xd9))
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return d.str(key, path, n)
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (b - 0xdc))
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return d.array(key, path, n)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (b - 0xde))
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return d.mapValue(key, path, n)
+	}
+	return nil, d.fail(fmt.Errorf("invalid type byte 0x%02x", b))
+}
+
+// number returns an integer node
+func (d *msgpackDecoder) number(key, path string, n int64) (*Node, error) {
+	return BuildTree(json.Number(strconv.FormatInt(n, 10)), key, path), nil
+}
+
+// str reads a string of n bytes. Strings that are not valid UTF-8 are
+// shown as byte strings.
+func (d *msgpackDecoder) str(key, path string, n uint64) (*Node, error) {
+	data, err := d.next(n)
+	if err != nil {
+		return nil, d.fail(err)
+	}
+	if !utf8.Valid(data) {
+		return d.bytes(key, path, data), nil
+	}
+	return BuildTree(string(data), key, path), nil
+}
+
+// bytes returns a byte string node holding a copy of data
+func (d *msgpackDecoder) bytes(key, path string, data []byte) *Node {
+	return &Node{Key: key, Path: path, Type: BytesNode, Value: Bytes(append([]byte(nil), data...))}
+}
+
+// array reads an array of n elements
+func (d *msgpackDecoder) array(key, path string, n uint64) (*Node, error) {
+	count, err := d.count(n, 1)
+	if err != nil {
+		return nil, d.fail(err)
+	}
+	node := &Node{Key: key, Path: path, Type: ArrayNode}
+	return node, readBinaryArray(node, count, nil, d.decode)
+}
+
+// mapValue reads a map of n entries
+func (d *msgpackDecoder) mapValue(key, path string, n uint64) (*Node, error) {
+	count, err := d.count(n, 2)
+	if err != nil {
+		return nil, d.fail(err)
+	}
+	node := &Node{Key: key, Path: path, Type: ObjectNode}
+	return node, readBinaryMap(node, count, nil, d.decode)
+}
+
+// ext reads an extension value with n bytes of data. Timestamps become
+// datetime nodes; other types are byte strings tagged with their type.
+func (d *msgpackDecoder) ext(key, path string, n uint64) (*Node, error) {
+	typ, err := d.readByte()
+	if err != nil {
+		return nil, d.fail(err)
+	}
+	data, err := d.next(n)
+	if err != nil {
+		return nil, d.fail(err)
+	}
+
+	if int8(typ) == msgpackTimestamp {
+		t, err := msgpackTime(data)
+		if err != nil {
+			return nil, d.fail(err)
+		}
+		return &Node{Key: key, Path: path, Type: DateTimeNode, Value: t.UTC().Format(time.RFC3339Nano)}, nil
+	}
+
+	node := d.bytes(key, path, data)
+	node.Tag = fmt.Sprintf("ext %d", int8(typ))
+	return node, nil
+}
+
+// msgpackTime decodes the data of a timestamp extension in any of its
+// 32, 64 and 96-bit forms
+func msgpackTime(data []byte) (time.Time, error) {
+	switch len(data) {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0), nil
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(v&(1<<34-1)), int64(v>>34)), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data)
+		return time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(nsec)), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp length %d", len(data))
+}
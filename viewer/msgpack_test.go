@@ -0,0 +1,167 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// encodeMessagePack encodes v with the reference encoder, with map keys
+// sorted so that entry order is known
+func encodeMessagePack(t *testing.T, v interface{}, compactInts bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(compactInts)
+	if err := enc.Encode(v); err != nil {
+		t.Fatalf("encoding %v: %v", v, err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseMessagePackRoundTrip(t *testing.T) {
+	long := strings.Repeat("x", 70000)
+	sixteen := make([]interface{}, 16)
+	for i := range sixteen {
+		sixteen[i] = i
+	}
+
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "null"},
+		{true, "true"},
+		{false, "false"},
+		{0, "0"},
+		{127, "127"},
+		{128, "128"},
+		{255, "255"},
+		{256, "256"},
+		{65535, "65535"},
+		{65536, "65536"},
+		{int64(1) << 32, "4294967296"},
+		{-1, "-1"},
+		{-32, "-32"},
+		{-33, "-33"},
+		{-128, "-128"},
+		{-129, "-129"},
+		{-32768, "-32768"},
+		{-32769, "-32769"},
+		{int64(math.MinInt32) - 1, "-2147483649"},
+		{int64(math.MinInt64), "-9223372036854775808"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{float32(1.5), "1.5"},
+		{0.1, "0.1"},
+		{math.NaN(), "NaN"},
+		{math.Inf(-1), "-Infinity"},
+		{"", `""`},
+		{"héllo", `"héllo"`},
+		{strings.Repeat("a", 31), fmt.Sprintf("%q", strings.Repeat("a", 31))},
+		{strings.Repeat("a", 32), fmt.Sprintf("%q", strings.Repeat("a", 32))},
+		{strings.Repeat("a", 300), fmt.Sprintf("%q", strings.Repeat("a", 300))},
+		{long, fmt.Sprintf("%q", long)},
+		{[]byte{1, 2, 3}, "b'010203'"},
+		{make([]byte, 300), "b'" + strings.Repeat("00", 300) + "'"},
+		{[]interface{}{}, "[]"},
+		{[]interface{}{1, "a", nil}, `[1,"a",null]`},
+		{sixteen, "[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15]"},
+		{map[string]interface{}{}, "{}"},
+		{map[string]interface{}{"b": 1, "a": []interface{}{true}}, `{"a":[true],"b":1}`},
+		{map[int]string{1: "x"}, `{"1":"x"}`},
+		{time.Unix(1718000000, 0), "t'2024-06-10T06:13:20Z'"},
+		{time.Unix(1718000000, 500), "t'2024-06-10T06:13:20.0000005Z'"},
+		{time.Unix(-1, 0), "t'1969-12-31T23:59:59Z'"},
+		{time.Unix(1<<35, 0), "t'3058-10-26T03:46:08Z'"},
+	}
+	for _, tt := range tests {
+		for _, compact := range []bool{false, true} {
+			data := encodeMessagePack(t, tt.value, compact)
+			name := fmt.Sprintf("%T %x", tt.value, data)
+			if len(name) > 60 {
+				name = name[:60]
+			}
+
+			root, err := parseMessagePack(data)
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				continue
+			}
+			if got := dumpTree(root); got != tt.want {
+				t.Errorf("%s: got %.80s, want %.80s", name, got, tt.want)
+			}
+			checkTruncations(t, name, data, parseMessagePack)
+		}
+	}
+}
+
+func TestParseMessagePackEncodings(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want string
+	}{
+		{"d40501", "b'01'<ext 5>"},                  // fixext 1
+		{"c703f0010203", "b'010203'<ext -16>"},      // ext 8
+		{"a2fffe", "b'fffe'"},                       // str that is not UTF-8
+		{"0102", "[1,2]"},                           // sequence of two values
+		{"81a161c0a162", `[{"a":null},"b"]`},        // sequence of a map and a string
+		{"82a161c0a16101", `{"a":1}`},               // repeated key, later wins
+		{"d1ff00", "-256"},                          // int 16 sign extension
+		{"cdffff", "65535"},                         // uint 16, no sign extension
+		{"d6ff00000000", "t'1970-01-01T00:00:00Z'"}, // 32-bit timestamp
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.hex)
+		root, err := parseMessagePack(data)
+		if err != nil {
+			t.Errorf("%s: %v", tt.hex, err)
+			continue
+		}
+		if got := dumpTree(root); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.hex, got, tt.want)
+		}
+	}
+}
+
+func TestParseMessagePackErrors(t *testing.T) {
+	tests := []struct {
+		hex     string
+		partial string // the tree returned alongside the error, if any
+		err     string
+	}{
+		{"c1", "", "byte 0: invalid type byte 0xc1"},
+		{"91c1", "[]", "byte 1: invalid type byte 0xc1"},
+		{"dcffff01", "", "byte 0: unexpected end of data"},
+		{"ddffffffff00", "", "byte 0: unexpected end of data"},
+		{"dfffffffff0000", "", "byte 0: unexpected end of data"},
+		{"dbffffffff61", "", "byte 0: unexpected end of data"},
+		{"c6ffffffff00", "", "byte 0: unexpected end of data"},
+		{"c9ffffffff0500", "", "byte 0: unexpected end of data"},
+		{"930102", "", "byte 0: unexpected end of data"},
+		{"9201a361", "[1]", "byte 2: unexpected end of data"},
+		{"82a16101a162", `{"a":1}`, "byte 6: unexpected end of data"},
+		{"01c1", "[1]", "byte 1: invalid type byte 0xc1"},
+		{"c703ff000000", "", "byte 0: invalid timestamp length 3"},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.hex)
+		root, err := parseMessagePack(data)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("%s: error %v, want %s", tt.hex, err, tt.err)
+		}
+		got := ""
+		if root != nil {
+			got = dumpTree(root)
+		}
+		if got != tt.partial {
+			t.Errorf("%s: partial tree %s, want %q", tt.hex, got, tt.partial)
+		}
+	}
+}
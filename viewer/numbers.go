@@ -0,0 +1,83 @@
+package viewer
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// isInexactNumber reports whether a number literal changes value when it is
+// parsed into a float64 and formatted back, as happens to 64-bit IDs or
+// high-precision decimals in most JSON tooling
+func isInexactNumber(v interface{}) bool {
+	n, ok := v.(json.Number)
+	if !ok {
+		return false
+	}
+	exact, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return false
+	}
+	f, err := n.Float64()
+	if err != nil || math.IsInf(f, 0) {
+		return true
+	}
+	rounded, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return rounded.Cmp(exact) != 0
+}
+
+// valuesEqual compares two decoded values. Numbers compare exactly by
+// value, whether they are literals or float64, so that a literal beyond
+// float64 precision matches only itself.
+func valuesEqual(a, b interface{}) bool {
+	x, okx := exactNumber(a)
+	y, oky := exactNumber(b)
+	if okx && oky {
+		return x.Cmp(y) == 0
+	}
+	return reflect.DeepEqual(plainNumber(a), plainNumber(b))
+}
+
+// exactNumber returns the exact value of a number literal or float64. A
+// float64 is taken at its shortest decimal form, which is how it was
+// written in a query or a document.
+func exactNumber(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(string(n))
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, false
+		}
+		return new(big.Rat).SetString(strconv.FormatFloat(n, 'g', -1, 64))
+	}
+	return nil, false
+}
+
+// plainNumber converts a number literal to float64, leaving other values as-is
+func plainNumber(v interface{}) interface{} {
+	if n, ok := v.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// serializableNumber returns v, or the text of a number that JSON cannot
+// hold, such as NaN or Infinity, as a string so that it still serializes
+func serializableNumber(v interface{}) interface{} {
+	switch n := v.(type) {
+	case json.Number:
+		if !json.Valid([]byte(n)) {
+			return string(n)
+		}
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return string(floatNumber(n, 64))
+		}
+	}
+	return v
+}
@@ -0,0 +1,410 @@
+package viewer
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// reflectBuilder builds trees for Go values other than decoded JSON, such
+// as structs, typed maps and slices, following the rules of encoding/json
+// for field names and marshalers. Values are converted to plain maps and
+// slices, so that JSONPath queries work on them as on decoded JSON.
+type reflectBuilder struct {
+	idx treeIndex
+	// visiting holds the pointers, maps and slices being expanded, to cut
+	// cycles
+	visiting map[visit]bool
+}
+
+// visit identifies a pointer, map or slice by its address and type. The
+// type tells apart a struct and a slice of its first field, which share
+// an address without one containing the other.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func newReflectBuilder(idx treeIndex) *reflectBuilder {
+	return &reflectBuilder{idx: idx, visiting: make(map[visit]bool)}
+}
+
+// enter marks a pointer, map or slice as being expanded. It reports false,
+// tagging node, when v is already being expanded and so contains itself.
+func (b *reflectBuilder) enter(node *Node, v reflect.Value) bool {
+	key := visit{v.Pointer(), v.Type()}
+	if b.visiting[key] {
+		node.Tag = "cycle"
+		return false
+	}
+	b.visiting[key] = true
+	return true
+}
+
+// leave ends the expansion of a value marked by enter
+func (b *reflectBuilder) leave(v reflect.Value) {
+	delete(b.visiting, visit{v.Pointer(), v.Type()})
+}
+
+// build creates the tree for a reflected value
+func (b *reflectBuilder) build(v reflect.Value, key, path string) *Node {
+	node := &Node{Key: key, Path: path, Type: NullNode}
+
+	// Follow interfaces and pointers; nil ones are null
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) {
+		if v.IsNil() {
+			return node
+		}
+		if v.Kind() == reflect.Pointer {
+			if !b.enter(node, v) {
+				return node
+			}
+			defer b.leave(v)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return node
+	}
+
+	// Decoded JSON held in an interface takes the usual route
+	if v.CanInterface() {
+		switch data := v.Interface().(type) {
+		case map[string]interface{}, []interface{}, string, json.Number, float64, bool:
+			return b.value(data, key, path)
+		}
+	}
+
+	if marshaled := b.marshaled(v, key, path); marshaled != nil {
+		return marshaled
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		node.Type = BoolNode
+		node.Value = v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		node.Type = NumberNode
+		node.Value = json.Number(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		node.Type = NumberNode
+		node.Value = json.Number(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		node.Type = NumberNode
+		node.Value = floatNumber(v.Float(), v.Type().Bits())
+	case reflect.Complex64, reflect.Complex128:
+		node.Type = StringNode
+		node.Value = strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits())
+	case reflect.String:
+		node.Type = StringNode
+		node.Value = v.String()
+	case reflect.Slice:
+		if v.IsNil() {
+			return node
+		}
+		if isByteSlice(v.Type()) {
+			node.Type = BytesNode
+			node.Value = Bytes(append([]byte(nil), v.Bytes()...))
+			return node
+		}
+		if !b.enter(node, v) {
+			return node
+		}
+		defer b.leave(v)
+		b.array(node, v)
+	case reflect.Array:
+		b.array(node, v)
+	case reflect.Map:
+		if v.IsNil() || !b.enter(node, v) {
+			return node
+		}
+		defer b.leave(v)
+		b.mapValue(node, v)
+	case reflect.Struct:
+		b.structValue(node, v)
+	default:
+		// Channels, functions and the like have no data to show
+		node.Tag = v.Type().String()
+	}
+	return node
+}
+
+// marshaled builds the tree for a value that marshals itself, or returns
+// nil. Times become datetime nodes; json.Marshaler output is parsed as
+// JSON and encoding.TextMarshaler output becomes a string.
+func (b *reflectBuilder) marshaled(v reflect.Value, key, path string) *Node {
+	if v.Type() == timeType && v.CanInterface() {
+		t := v.Interface().(time.Time)
+		return &Node{Key: key, Path: path, Type: DateTimeNode, Value: t.Format(time.RFC3339Nano)}
+	}
+
+	// Methods with pointer receivers are usable on addressable values
+	if !v.Type().Implements(jsonMarshalerType) && !v.Type().Implements(textMarshalerType) && v.CanAddr() {
+		v = v.Addr()
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+
+	switch m := v.Interface().(type) {
+	case json.Marshaler:
+		out, err := m.MarshalJSON()
+		if err == nil {
+			var node *Node
+			if node, err = parseJSON(out, key, path); err == nil {
+				return node
+			}
+		}
+		return &Node{Key: key, Path: path, Type: StringNode, Value: string(out), Err: err}
+	case encoding.TextMarshaler:
+		out, err := m.MarshalText()
+		return &Node{Key: key, Path: path, Type: StringNode, Value: string(out), Err: err}
+	}
+	return nil
+}
+
+// array fills node with the elements of a slice or array
+func (b *reflectBuilder) array(node *Node, v reflect.Value) {
+	node.Type = ArrayNode
+	values := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		child := b.build(v.Index(i), fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", node.Path, i))
+		child.Parent = node
+		node.Children = append(node.Children, child)
+		values = append(values, child.Value)
+	}
+	node.Value = values
+}
+
+// mapValue fills node with the entries of a map, sorted by key
+func (b *reflectBuilder) mapValue(node *Node, v reflect.Value) {
+	node.Type = ObjectNode
+	keys := make([]string, 0, v.Len())
+	entries := make(map[string]reflect.Value, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		k := mapKey(iter.Key())
+		keys = append(keys, k)
+		entries[k] = iter.Value()
+	}
+	sort.Strings(keys)
+
+	value := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		child := b.build(entries[k], k, node.Path+"."+k)
+		child.Parent = node
+		node.Children = append(node.Children, child)
+		value[k] = child.Value
+	}
+	node.Value = value
+}
+
+// structValue fills node with the fields of a struct, in declaration order
+func (b *reflectBuilder) structValue(node *Node, v reflect.Value) {
+	node.Type = ObjectNode
+	fields := cachedFields(v.Type())
+	value := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		child := b.build(fv, f.name, node.Path+"."+f.name)
+		if f.quoted && (child.Type == NumberNode || child.Type == BoolNode) {
+			child.Type = StringNode
+			child.Value = fmt.Sprintf("%v", child.Value)
+		}
+		child.Parent = node
+		node.Children = append(node.Children, child)
+		value[f.name] = child.Value
+	}
+	node.Value = value
+}
+
+// mapKey renders a map key as encoding/json does: text marshalers first,
+// then strings as they are, then integers
+func mapKey(k reflect.Value) string {
+	if k.CanInterface() {
+		if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+			if out, err := tm.MarshalText(); err == nil {
+				return string(out)
+			}
+		}
+	}
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	}
+	return fmt.Sprint(k)
+}
+
+// isByteSlice reports whether t is a slice of bytes that encoding/json
+// would encode as base64, rather than element by element
+func isByteSlice(t reflect.Type) bool {
+	if t.Elem().Kind() != reflect.Uint8 {
+		return false
+	}
+	p := reflect.PointerTo(t.Elem())
+	return !p.Implements(jsonMarshalerType) && !p.Implements(textMarshalerType)
+}
+
+// isEmptyValue reports whether a field tagged omitempty is left out
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// fieldByIndex returns a possibly promoted field, or false when it sits
+// behind a nil embedded pointer
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// structField is a struct field as encoding/json sees it
+type structField struct {
+	name      string
+	index     []int
+	tagged    bool // named by a json tag
+	omitEmpty bool
+	quoted    bool // the ",string" option
+	depth     int  // how deeply the field is embedded
+}
+
+// fieldCache holds the fields of each struct type seen
+var fieldCache sync.Map
+
+// cachedFields returns the fields of a struct type
+func cachedFields(t reflect.Type) []structField {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	fields, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return fields.([]structField)
+}
+
+// typeFields lists the fields of a struct type, promoting the fields of
+// embedded structs. When several fields share a name, the least deeply
+// embedded one wins, then the only one named by a tag; otherwise the name
+// is left out, as with encoding/json.
+func typeFields(t reflect.Type) []structField {
+	var all []structField
+	collectFields(t, nil, 0, map[reflect.Type]bool{t: true}, &all)
+
+	byName := make(map[string][]int)
+	for i, f := range all {
+		byName[f.name] = append(byName[f.name], i)
+	}
+
+	var fields []structField
+	for i, f := range all {
+		if dominantField(all, byName[f.name]) == i {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// collectFields gathers the fields of t in declaration order, descending
+// into embedded structs
+func collectFields(t reflect.Type, index []int, depth int, visiting map[reflect.Type]bool, fields *[]structField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		idx := append(index[:len(index):len(index)], i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if !visiting[ft] {
+				visiting[ft] = true
+				collectFields(ft, idx, depth+1, visiting, fields)
+				delete(visiting, ft)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		f := structField{name: name, index: idx, tagged: name != "", depth: depth}
+		if name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "string":
+				f.quoted = true
+			}
+		}
+		*fields = append(*fields, f)
+	}
+}
+
+// dominantField returns which of the fields sharing a name is shown, or -1
+func dominantField(all []structField, candidates []int) int {
+	depth := all[candidates[0]].depth
+	for _, i := range candidates[1:] {
+		depth = min(depth, all[i].depth)
+	}
+
+	var shallowest, tagged []int
+	for _, i := range candidates {
+		if all[i].depth != depth {
+			continue
+		}
+		shallowest = append(shallowest, i)
+		if all[i].tagged {
+			tagged = append(tagged, i)
+		}
+	}
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0]
+	case len(tagged) == 1:
+		return tagged[0]
+	}
+	return -1
+}
@@ -0,0 +1,137 @@
+package viewer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type renamed struct {
+	Name    string `json:"name"`
+	Skipped string `json:"-"`
+	Dash    string `json:"-,"`
+	Empty   string `json:",omitempty"`
+	Zero    int    `json:"zero,omitempty"`
+	Count   int    `json:"count,string"`
+	OK      bool   `json:",string"`
+	hidden  string
+}
+
+type Base struct {
+	ID   int
+	Name string
+}
+
+type Extra struct {
+	Name string `json:"Name"`
+	Note string
+}
+
+type embedding struct {
+	Base
+	*Extra
+	Own string
+}
+
+type conflicting struct {
+	Base
+	Other
+}
+
+type Other struct {
+	ID   int
+	Kind string
+}
+
+type shallow struct {
+	Base
+	ID string
+}
+
+type upper string
+
+func (u upper) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(u))), nil
+}
+
+type point struct{ X, Y int }
+
+func (p point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.X, p.Y)), nil
+}
+
+type rawJSON struct{}
+
+func (rawJSON) MarshalJSON() ([]byte, error) {
+	return []byte(`{"z":1,"a":[true]}`), nil
+}
+
+type brokenJSON struct{}
+
+func (brokenJSON) MarshalJSON() ([]byte, error) {
+	return []byte(`{nope`), nil
+}
+
+type linked struct {
+	Name string
+	Next *linked
+}
+
+type selfMap map[string]interface{}
+
+func TestBuildTreeGoValues(t *testing.T) {
+	loop := &linked{Name: "a"}
+	loop.Next = &linked{Name: "b", Next: loop}
+	m := selfMap{"n": 1}
+	m["self"] = m
+	plain := map[string]interface{}{"n": 1}
+	plain["self"] = plain
+	list := []interface{}{1, nil}
+	list[1] = list
+	shared := &Base{ID: 1}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"tags", renamed{Name: "x", Skipped: "s", Dash: "d", Count: 3, OK: true, hidden: "h"},
+			`{"name":"x","-":"d","count":"3","OK":"true"}`},
+		{"omitempty set", renamed{Empty: "e", Zero: 1}, `{"name":"","-":"","Empty":"e","zero":1,"count":"0","OK":"false"}`},
+		{"embedded", embedding{Base: Base{ID: 1, Name: "base"}, Extra: &Extra{Name: "extra", Note: "n"}, Own: "o"},
+			`{"ID":1,"Name":"extra","Note":"n","Own":"o"}`},
+		{"nil embedded pointer", embedding{Base: Base{ID: 1, Name: "base"}, Own: "o"}, `{"ID":1,"Own":"o"}`},
+		{"conflict at same depth", conflicting{Base{1, "b"}, Other{2, "k"}}, `{"Name":"b","Kind":"k"}`},
+		{"shallower field wins", shallow{Base{1, "b"}, "top"}, `{"Name":"b","ID":"top"}`},
+		{"time", time.Date(2024, 6, 10, 6, 13, 20, 5e8, time.UTC), "t'2024-06-10T06:13:20.5Z'"},
+		{"bytes", []byte{0xca, 0xfe}, "b'cafe'"},
+		{"json marshaler", rawJSON{}, `{"z":1,"a":[true]}`},
+		{"broken json marshaler", brokenJSON{}, `"{nope"`},
+		{"text marshaler keys", map[upper]int{"b": 2, "a": 1}, `{"A":1,"B":2}`},
+		{"text marshaler struct keys", map[point]int{{1, 2}: 3}, `{"1,2":3}`},
+		{"text marshaler value", []upper{"x"}, `["X"]`},
+		{"int keys", map[int]string{10: "ten", 9: "nine"}, `{"10":"ten","9":"nine"}`},
+		{"pointer cycle", loop, `{"Name":"a","Next":{"Name":"b","Next":null<cycle>}}`},
+		{"map cycle", m, `{"n":1,"self":null<cycle>}`},
+		{"plain map cycle", plain, `{"n":1,"self":null<cycle>}`},
+		{"slice cycle", list, `[1,null<cycle>]`},
+		{"repeated pointer is not a cycle", []*Base{shared, shared}, `[{"ID":1,"Name":""},{"ID":1,"Name":""}]`},
+		{"channel", make(chan int), "null<chan int>"},
+	}
+	for _, tt := range tests {
+		if got := dumpTree(BuildTree(tt.value, "", "$")); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildTreeCycleValuesAreAcyclic(t *testing.T) {
+	m := selfMap{}
+	m["self"] = m
+	root := BuildTree(m, "", "$")
+	value, ok := root.Value.(map[string]interface{})
+	if !ok || value["self"] != nil {
+		t.Errorf("root value = %#v, want a plain map with a null cycle", root.Value)
+	}
+}
@@ -0,0 +1,266 @@
+package viewer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ReloadMsg replaces the viewer's data with a freshly loaded model's, e.g.
+// when the viewed file changes on disk. Expanded nodes, the cursor, search
+// matches and any active filter are kept by path. When Err is set the
+// current data is kept and the error is shown instead.
+type ReloadMsg struct {
+	Model Model
+	Err   error
+}
+
+// clearChangesMsg ends the change highlight of a reload
+type clearChangesMsg struct {
+	gen int
+}
+
+// errNoReload is reported when reloading without Config.Reload
+var errNoReload = errors.New("no reload source configured")
+
+// Reload loads the source again with Config.Reload and highlights what
+// changed. The returned command clears the highlight after
+// Config.ChangeHighlight and should be passed back to Bubble Tea.
+func (m Model) Reload() (Model, tea.Cmd) {
+	msg := ReloadMsg{Err: errNoReload}
+	if m.config.Reload != nil {
+		msg.Model, msg.Err = m.config.Reload()
+	}
+	cmd := m.handleReload(msg)
+	return m, cmd
+}
+
+// reloadCmd loads the source again in the background for the reload key
+func (m Model) reloadCmd() tea.Cmd {
+	reload := m.config.Reload
+	if reload == nil {
+		return func() tea.Msg { return ReloadMsg{Err: errNoReload} }
+	}
+	return func() tea.Msg {
+		next, err := reload()
+		return ReloadMsg{Model: next, Err: err}
+	}
+}
+
+// reload adopts the data of next, keeping the view state
+func (m *Model) reload(next Model) {
+	previous := m.source
+
+	m.source = next.source
+	m.index = next.index
+	m.rawData = next.rawData
+	m.nodeCount = next.nodeCount
+	m.lineCount = next.lineCount
+	m.loadErr = next.loadErr
+	if next.fileSize > 0 {
+		m.fileSize = next.fileSize
+		m.compression = next.compression
+		m.compressedSize = next.compressedSize
+	}
+
+	m.changes, m.removed = nil, nil
+	if m.config.ChangeHighlight > 0 {
+		m.changes, m.removed = diffTrees(previous, m.source)
+	}
+	m.rebuildTree()
+}
+
+// handleReload applies a ReloadMsg and reports the outcome in the status
+// line, returning the command that ends the change highlight
+func (m *Model) handleReload(msg ReloadMsg) tea.Cmd {
+	now := time.Now().Format("15:04:05")
+	switch {
+	case msg.Err != nil:
+		m.setStatus(fmt.Sprintf("Reload failed at %s, showing previous data: %v", now, msg.Err), true)
+		return nil
+	case msg.Model.loadErr != nil:
+		m.reload(msg.Model)
+		m.setStatus(fmt.Sprintf("Reloaded at %s with errors: %v", now, msg.Model.loadErr), true)
+	default:
+		m.reload(msg.Model)
+		m.setStatus(fmt.Sprintf("Reloaded at %s%s", now, m.changeSummary()), false)
+	}
+	if next := msg.Model; next.status != "" {
+		m.setStatus(m.status+" - "+next.status, m.statusErr || next.statusErr)
+		m.statusSticky = true
+	}
+
+	if len(m.changes) == 0 {
+		return nil
+	}
+	m.changeGen++
+	gen := m.changeGen
+	return tea.Tick(m.config.ChangeHighlight, func(time.Time) tea.Msg {
+		return clearChangesMsg{gen: gen}
+	})
+}
+
+// setStatus shows a message in the footer until the next key press
+func (m *Model) setStatus(message string, isErr bool) {
+	m.status = message
+	m.statusErr = isErr
+	m.statusSticky = false
+}
+
+// changeKind is how a node differs from the previous load
+type changeKind int
+
+const (
+	changeNone changeKind = iota
+	changeAdded
+	changeRemoved
+	changeChanged
+)
+
+// diffTrees compares two loads by path. Containers holding a change are
+// marked changed themselves so that collapsed nodes still show it. The
+// removed nodes are returned from the previous tree so that they can be
+// shown in place until the highlight ends. Array elements are matched by
+// index rather than by value, which keeps removed nodes placeable by path
+// but marks every element after an insertion or removal as changed.
+func diffTrees(previous, current *Node) (map[string]changeKind, []*Node) {
+	changes := make(map[string]changeKind)
+	var removed []*Node
+
+	var mark func(n *Node, kind changeKind)
+	mark = func(n *Node, kind changeKind) {
+		changes[n.Path] = kind
+		for _, child := range n.Children {
+			mark(child, kind)
+		}
+	}
+
+	var diff func(a, b *Node) bool
+	diff = func(a, b *Node) bool {
+		if a.Type != b.Type {
+			changes[b.Path] = changeChanged
+			return true
+		}
+		if a.Type != ObjectNode && a.Type != ArrayNode {
+			if valuesEqual(a.Value, b.Value) {
+				return false
+			}
+			changes[b.Path] = changeChanged
+			return true
+		}
+
+		changed := false
+		old := make(map[string]*Node, len(a.Children))
+		for _, child := range a.Children {
+			old[child.Path] = child
+		}
+		for _, child := range b.Children {
+			if prev, ok := old[child.Path]; ok {
+				if diff(prev, child) {
+					changed = true
+				}
+				delete(old, child.Path)
+				continue
+			}
+			mark(child, changeAdded)
+			changed = true
+		}
+		for _, child := range a.Children {
+			if _, gone := old[child.Path]; gone {
+				mark(child, changeRemoved)
+				removed = append(removed, child)
+				changed = true
+			}
+		}
+		if changed {
+			changes[b.Path] = changeChanged
+		}
+		return changed
+	}
+
+	diff(previous, current)
+	return changes, removed
+}
+
+// changeSummary counts the leaf changes of the last reload for the status
+// line
+func (m *Model) changeSummary() string {
+	var added, removed, changed int
+	var count func(n *Node)
+	count = func(n *Node) {
+		if len(n.Children) > 0 {
+			for _, child := range n.Children {
+				count(child)
+			}
+			return
+		}
+		switch m.changes[n.Path] {
+		case changeAdded:
+			added++
+		case changeRemoved:
+			removed++
+		case changeChanged:
+			changed++
+		}
+	}
+	count(m.source)
+	for _, n := range m.removed {
+		count(n)
+	}
+
+	if added+removed+changed == 0 {
+		return ", no changes"
+	}
+	return fmt.Sprintf(", %d added, %d removed, %d changed", added, removed, changed)
+}
+
+// insertRemoved shows the nodes removed by the last reload at their old
+// positions in the displayed tree, where their parent is still shown
+func (m *Model) insertRemoved() {
+	if len(m.removed) == 0 {
+		return
+	}
+
+	byPath := make(map[string]*Node)
+	var walk func(*Node)
+	walk = func(n *Node) {
+		byPath[n.Path] = n
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(m.root)
+
+	for _, gone := range m.removed {
+		parent := byPath[gone.Parent.Path]
+		if parent == nil {
+			continue
+		}
+		at := len(parent.Children)
+		for i, sibling := range gone.Parent.Children {
+			if sibling == gone {
+				at = min(i, at)
+			}
+		}
+
+		ghost := gone.Clone()
+		ghost.Parent = parent
+		parent.Children = append(parent.Children[:at], append([]*Node{ghost}, parent.Children[at:]...)...)
+	}
+}
+
+// changeStyle returns the theme style for a node changed by the last reload
+func (m Model) changeStyle(node *Node) (lipgloss.Style, bool) {
+	switch m.changes[node.Path] {
+	case changeAdded:
+		return m.config.Theme.Added, true
+	case changeRemoved:
+		return m.config.Theme.Removed, true
+	case changeChanged:
+		return m.config.Theme.Changed, true
+	}
+	return lipgloss.Style{}, false
+}
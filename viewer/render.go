@@ -3,6 +3,7 @@ package viewer
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -12,7 +13,7 @@ func (m *Model) updateViewNodes() {
 	m.viewNodes = nil
 	m.collectViewNodes(m.root)
 
-	if m.filter != "" && !m.jsonpathMode {
+	if m.filter != "" && !m.isJSONPathFilter() {
 		filtered := make([]*Node, 0)
 		filterLower := strings.ToLower(m.filter)
 		for _, node := range m.viewNodes {
@@ -85,27 +86,58 @@ func (m Model) renderNode(node *Node, isCursor bool) string {
 	}
 
 	var valuePart string
-	switch node.Type {
-	case ObjectNode:
+	switch {
+	case node.Raw != "" && !node.Expanded:
+		// Values decoded from a string show that string until expanded
+		valuePart = m.config.Theme.String.Render(formatRaw(node.Raw))
+	case node.Type == ObjectNode:
 		if node.Expanded {
 			valuePart = "{"
 		} else {
 			valuePart = fmt.Sprintf("{...} (%d items)", len(node.Children))
 		}
-	case ArrayNode:
+	case node.Type == ArrayNode:
 		if node.Expanded {
 			valuePart = "["
 		} else {
 			valuePart = fmt.Sprintf("[...] (%d items)", len(node.Children))
 		}
-	case StringNode:
+	case node.Type == StringNode:
 		valuePart = m.config.Theme.String.Render(fmt.Sprintf("\"%s\"", node.Value))
-	case NumberNode:
+	case node.Type == NumberNode:
 		valuePart = m.config.Theme.Number.Render(fmt.Sprintf("%v", node.Value))
-	case BoolNode:
+		if m.config.MarkInexactNumbers && isInexactNumber(node.Value) {
+			valuePart += " " + m.config.Theme.Status.Render("(inexact as float64)")
+		}
+	case node.Type == BoolNode:
 		valuePart = m.config.Theme.Bool.Render(fmt.Sprintf("%v", node.Value))
-	case NullNode:
+	case node.Type == NullNode:
 		valuePart = m.config.Theme.Null.Render("null")
+	case node.Type == DateTimeNode:
+		valuePart = m.config.Theme.DateTime.Render(fmt.Sprintf("%v", node.Value))
+	case node.Type == BytesNode:
+		valuePart = m.config.Theme.Bytes.Render(formatBytes(node.Value.(Bytes), m.bytesBase64))
+	}
+
+	tag := node.Tag
+	if m.config.ShowTimestamps && (node.Raw == "" || node.Expanded) {
+		if t, ok := nodeTime(node); ok {
+			// JWT time claims are tagged with their date, which this replaces
+			if tag == t.UTC().Format(time.RFC3339) {
+				tag = ""
+			}
+			valuePart += " " + m.config.Theme.Status.Render("("+formatTimestamp(t, m.config.TimeZone, time.Now())+")")
+		}
+	}
+	if tag != "" {
+		valuePart += " " + m.config.Theme.Status.Render("("+tag+")")
+	}
+
+	if node.Err != nil {
+		valuePart += " " + m.config.Theme.Error.Render("✗ "+node.Err.Error())
+	}
+	if node.Comment != "" {
+		valuePart += "  " + m.config.Theme.Comment.Render("// "+node.Comment)
 	}
 
 	// Check if this node is a search match
@@ -125,6 +157,8 @@ func (m Model) renderNode(node *Node, isCursor bool) string {
 		line = m.config.Theme.Match.Render(line)
 	} else if isCursor {
 		line = m.config.Theme.Cursor.Render(line)
+	} else if style, ok := m.changeStyle(node); ok {
+		line = style.Render(line)
 	}
 
 	return line
@@ -140,14 +174,23 @@ func (m Model) renderHeader() string {
 	if m.filename != "" {
 		title = m.config.Theme.Header.Render(fmt.Sprintf("Bonsai - %s", m.filename))
 	}
+	if len(m.tabs) > 1 {
+		title = m.renderTabs()
+	}
 
 	stats := ""
-	if m.fileSize > 0 {
+	if m.fileSize > 0 && m.compression != "" {
+		stats = m.config.Theme.Status.Render(fmt.Sprintf("Size: %.1fKB (%.1fKB %s) | Nodes: %d",
+			float64(m.fileSize)/1024, float64(m.compressedSize)/1024, m.compression, m.nodeCount))
+	} else if m.fileSize > 0 {
 		stats = m.config.Theme.Status.Render(fmt.Sprintf("Size: %.1fKB | Nodes: %d",
 			float64(m.fileSize)/1024, m.nodeCount))
 	} else {
 		stats = m.config.Theme.Status.Render(fmt.Sprintf("Nodes: %d", m.nodeCount))
 	}
+	if m.loadErr != nil {
+		stats += "  " + m.config.Theme.Error.Render(fmt.Sprintf("✗ Partial: %v", m.loadErr))
+	}
 
 	var filterInfo string
 	if m.filterMode {
@@ -163,7 +206,7 @@ func (m Model) renderHeader() string {
 	} else if m.gotoMode {
 		filterInfo = m.config.Theme.Goto.Render(fmt.Sprintf("Goto: %s█", m.filter))
 	} else if m.filter != "" {
-		if strings.HasPrefix(m.filter, "$") {
+		if m.jsonpathQuery {
 			filterInfo = m.config.Theme.JSONPath.Render(fmt.Sprintf("Active JSONPath: %s", m.filter))
 		} else {
 			filterInfo = m.config.Theme.Filter.Render(fmt.Sprintf("Active Filter: %s", m.filter))
@@ -187,6 +230,20 @@ func (m Model) renderHeader() string {
 	return lipgloss.JoinVertical(lipgloss.Left, headerLine1, headerLine2)
 }
 
+// renderTabs renders the tab bar shown in place of the title
+func (m Model) renderTabs() string {
+	bar := m.config.Theme.Header.Render("Bonsai ")
+	for i, name := range m.tabs {
+		label := fmt.Sprintf(" %d:%s ", i+1, name)
+		if i == m.activeTab {
+			bar += m.config.Theme.Header.Reverse(true).Render(label)
+		} else {
+			bar += m.config.Theme.Status.Render(label)
+		}
+	}
+	return bar
+}
+
 // renderFooter renders the footer section (only in non-embedded mode)
 func (m Model) renderFooter() string {
 	if m.embedded {
@@ -207,6 +264,13 @@ func (m Model) renderFooter() string {
 		return m.config.Theme.Goto.Render("Press Enter to goto path, Esc to cancel")
 	}
 
+	if m.status != "" {
+		if m.statusErr {
+			return m.config.Theme.Error.Render(m.status)
+		}
+		return m.config.Theme.Status.Render(m.status)
+	}
+
 	helpText := m.config.Theme.Status.Render("Press ? for help")
 	return helpText
 }
@@ -242,8 +306,18 @@ func (m Model) renderManualHelp() string {
 	}
 
 	help.WriteString(helpStyle.Render("Utility:") + "\n")
-	help.WriteString("  r/Ctrl+R                Reset view\n")
-	help.WriteString("  ?, q/Esc                Help, Quit\n")
+	if m.config.Reload != nil {
+		help.WriteString("  r/Ctrl+R, R             Reset view, reload source\n")
+	} else {
+		help.WriteString("  r/Ctrl+R                Reset view\n")
+	}
+	help.WriteString("  x                       Show bytes as hex/base64\n")
+	help.WriteString("  o                       Sort list by time at cursor\n")
+	if len(m.tabs) > 1 {
+		help.WriteString("  Tab/Shift+Tab, 1-9, ?, q  Switch tab, Help, Quit\n")
+	} else {
+		help.WriteString("  ?, q/Esc                Help, Quit\n")
+	}
 
 	help.WriteString(titleStyle.Render("Press ? to close help"))
 
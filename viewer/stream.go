@@ -0,0 +1,47 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// parseJSONStream decodes a stream of concatenated top-level JSON values,
+// as printed by `docker inspect` or `kubectl ... -o json` in a loop, into a
+// synthetic array root holding one child per document
+func parseJSONStream(reader io.Reader) (*Node, error) {
+	var source bytes.Buffer
+	dec := json.NewDecoder(io.TeeReader(reader, &source))
+	dec.UseNumber()
+	d := &jsonDecoder{dec: dec, tee: &source}
+
+	root := &Node{Type: ArrayNode, Path: "$"}
+	docs := make([]interface{}, 0)
+	root.Value = docs
+
+	for i := 0; dec.More(); i++ {
+		doc, err := d.decodeNode(fmt.Sprintf("[%d]", i), fmt.Sprintf("$[%d]", i))
+		if doc == nil {
+			root.Err = err
+			return root, err
+		}
+		doc.Parent = root
+		root.Children = append(root.Children, doc)
+		docs = append(docs, doc.Value)
+		root.Value = docs
+
+		if err != nil {
+			return root, err
+		}
+	}
+
+	// More also stops at a stray closing delimiter, which Token reports
+	if _, err := dec.Token(); err != io.EOF {
+		if err == nil {
+			err = ErrTrailingData
+		}
+		return d.fail(root, err)
+	}
+	return root, nil
+}
@@ -20,6 +20,13 @@ func DefaultTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("244")),
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("220")).Foreground(lipgloss.Color("16")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("62")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("22")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("52")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("58")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("141")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("109")),
 	}
 }
 
@@ -41,6 +48,13 @@ func LightTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("242")),
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("226")).Foreground(lipgloss.Color("16")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("160")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("194")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("224")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("230")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("91")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("30")),
 	}
 }
 
@@ -62,6 +76,13 @@ func MonochromeTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("244")),
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("255")).Foreground(lipgloss.Color("16")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("255")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Faint(true),
+		Added:      lipgloss.NewStyle().Bold(true).Underline(true),
+		Removed:    lipgloss.NewStyle().Faint(true).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Bold(true),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Italic(true),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("250")).Underline(true),
 	}
 }
 
@@ -83,6 +104,13 @@ func TokyoNightTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("#9aa5ce")),           // Fg dark
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("#e0af68")).Foreground(lipgloss.Color("#1a1b26")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#414868")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("#f7768e")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("#565f89")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("#1f3a2c")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("#3f2330")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("#3d3520")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("#bb9af7")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("#73daca")),
 	}
 }
 
@@ -104,6 +132,13 @@ func CatppuccinMochaTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("#bac2de")),           // Subtext1
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("#f9e2af")).Foreground(lipgloss.Color("#1e1e2e")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#45475a")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("#2b3b2f")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("#3e2836")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("#3e3a2a")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("#cba6f7")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("#94e2d5")),
 	}
 }
 
@@ -125,6 +160,13 @@ func CatppuccinLatteTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("#6c6f85")),           // Subtext1
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("#df8e1d")).Foreground(lipgloss.Color("#eff1f5")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#9ca0b0")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("#d20f39")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("#8c8fa1")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("#d5ecd0")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("#f4d3d9")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("#f5e9c9")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("#8839ef")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("#179299")),
 	}
 }
 
@@ -146,6 +188,13 @@ func DraculaTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("#f8f8f2")),           // Foreground
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("#f1fa8c")).Foreground(lipgloss.Color("#282a36")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#6272a4")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("#ff5555")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("#6272a4")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("#24402e")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("#4a2533")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("#46432a")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("#bd93f9")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("#8be9fd")),
 	}
 }
 
@@ -167,6 +216,13 @@ func NordTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("#d8dee9")),           // Nord4
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("#ebcb8b")).Foreground(lipgloss.Color("#2e3440")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#434c5e")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("#bf616a")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("#4c566a")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("#3b4c3f")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("#4c3a40")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("#4d4a3a")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("#b48ead")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("#88c0d0")),
 	}
 }
 
@@ -188,5 +244,12 @@ func GruvboxTheme() Theme {
 		Breadcrumb: lipgloss.NewStyle().Foreground(lipgloss.Color("#a89984")),           // Light4
 		Match:      lipgloss.NewStyle().Background(lipgloss.Color("#fabd2f")).Foreground(lipgloss.Color("#1d2021")),
 		Border:     lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#504945")),
+		Error:      lipgloss.NewStyle().Foreground(lipgloss.Color("#fb4934")).Bold(true),
+		Comment:    lipgloss.NewStyle().Foreground(lipgloss.Color("#928374")).Faint(true),
+		Added:      lipgloss.NewStyle().Background(lipgloss.Color("#32361a")),
+		Removed:    lipgloss.NewStyle().Background(lipgloss.Color("#3c1f1e")).Strikethrough(true),
+		Changed:    lipgloss.NewStyle().Background(lipgloss.Color("#473c16")),
+		DateTime:   lipgloss.NewStyle().Foreground(lipgloss.Color("#d3869b")),
+		Bytes:      lipgloss.NewStyle().Foreground(lipgloss.Color("#8ec07c")),
 	}
 }
\ No newline at end of file
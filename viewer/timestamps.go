@@ -0,0 +1,111 @@
+package viewer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nodeTime returns the time a node holds, for numbers that look like epoch
+// timestamps and for RFC 3339 strings and datetimes
+func nodeTime(n *Node) (time.Time, bool) {
+	switch n.Type {
+	case NumberNode, StringNode, DateTimeNode:
+		return valueTime(n.Value)
+	}
+	return time.Time{}, false
+}
+
+// valueTime returns the time a value holds. Numbers are taken as seconds,
+// milliseconds, microseconds or nanoseconds since the epoch by their digit
+// count, which places them between 2001 and 2286; other numbers, such as
+// counts and small IDs, are not times.
+func valueTime(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		return epochTime(string(v))
+	case float64:
+		return epochTime(strconv.FormatFloat(v, 'f', -1, 64))
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
+// epochTime reads an epoch timestamp written in decimal. Only seconds may
+// have a fraction.
+func epochTime(s string) (time.Time, bool) {
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || whole[0] < '1' || whole[0] > '9' {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch len(whole) {
+	case 10:
+		var nsec int64
+		if hasFrac {
+			f, err := strconv.ParseFloat("0."+frac, 64)
+			if err != nil {
+				return time.Time{}, false
+			}
+			nsec = int64(f * 1e9)
+		}
+		return time.Unix(n, nsec), true
+	case 13:
+		return time.UnixMilli(n), !hasFrac
+	case 16:
+		return time.UnixMicro(n), !hasFrac
+	case 19:
+		return time.Unix(0, n), !hasFrac
+	}
+	return time.Time{}, false
+}
+
+// formatTimestamp renders a time in loc with how long ago it was
+func formatTimestamp(t time.Time, loc *time.Location, now time.Time) string {
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format("2006-01-02 15:04:05.999 MST") + ", " + formatAge(now.Sub(t))
+}
+
+// formatAge renders a duration as a rounded age, such as "3d ago", or
+// "in 2h" for times to come
+func formatAge(d time.Duration) string {
+	if d > -time.Second && d < time.Second {
+		return "just now"
+	}
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	const day = 24 * time.Hour
+	var age string
+	switch {
+	case d < time.Minute:
+		age = fmt.Sprintf("%ds", d/time.Second)
+	case d < time.Hour:
+		age = fmt.Sprintf("%dm", d/time.Minute)
+	case d < day:
+		age = fmt.Sprintf("%dh", d/time.Hour)
+	case d < 30*day:
+		age = fmt.Sprintf("%dd", d/day)
+	case d < 365*day:
+		age = fmt.Sprintf("%dmo", d/(30*day))
+	default:
+		age = fmt.Sprintf("%dy", d/(365*day))
+	}
+
+	if future {
+		return "in " + age
+	}
+	return age + " ago"
+}
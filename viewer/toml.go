@@ -0,0 +1,152 @@
+package viewer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// NewFromTOML creates a new JSON viewer from TOML data. Tables become
+// objects in source order, arrays of tables become arrays of objects, and
+// dates and times are shown as datetime nodes.
+func NewFromTOML(data []byte, config ...Config) (Model, error) {
+	root, err := parseTOML(data)
+	return newFromParsed(root, err, config...)
+}
+
+// parseTOML decodes a TOML document into a Node tree
+func parseTOML(data []byte) (*Node, error) {
+	var doc map[string]interface{}
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, newParseErrorAt(data, int64(perr.Position.Start), errors.New(perr.Message))
+		}
+		return nil, err
+	}
+
+	// The decoder returns Go maps; the metadata keeps the order in which
+	// keys appear, which is used to order each table's members
+	c := &tomlConverter{order: make(map[string][]string)}
+	seen := make(map[string]bool)
+	for _, key := range md.Keys() {
+		full := strings.Join(key, "\x00")
+		if seen[full] {
+			continue
+		}
+		seen[full] = true
+		parent := strings.Join(key[:len(key)-1], "\x00")
+		c.order[parent] = append(c.order[parent], key[len(key)-1])
+	}
+
+	return c.convert(doc, "", "$", nil), nil
+}
+
+// tomlConverter builds Node trees from decoded TOML values
+type tomlConverter struct {
+	// order lists the keys of each table, by the table's key path joined
+	// with NUL, in source order
+	order map[string][]string
+}
+
+// convert builds the subtree for a decoded TOML value. keyPath is the
+// value's TOML key, which locates its members' order; array indexes are
+// not part of it, so every table in an array of tables shares one order.
+func (c *tomlConverter) convert(data interface{}, key, path string, keyPath []string) *Node {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		node := &Node{Key: key, Path: path, Type: ObjectNode}
+		value := make(map[string]interface{}, len(v))
+		for _, k := range c.keys(v, keyPath) {
+			child := c.convert(v[k], k, path+"."+k, append(keyPath[:len(keyPath):len(keyPath)], k))
+			child.Parent = node
+			node.Children = append(node.Children, child)
+			value[k] = child.Value
+		}
+		node.Value = value
+		return node
+
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, table := range v {
+			items[i] = table
+		}
+		return c.convert(items, key, path, keyPath)
+
+	case []interface{}:
+		node := &Node{Key: key, Path: path, Type: ArrayNode}
+		values := make([]interface{}, 0, len(v))
+		for i, item := range v {
+			child := c.convert(item, fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", path, i), keyPath)
+			child.Parent = node
+			node.Children = append(node.Children, child)
+			values = append(values, child.Value)
+		}
+		node.Value = values
+		return node
+
+	case time.Time:
+		return &Node{Key: key, Path: path, Type: DateTimeNode, Value: formatTOMLTime(v)}
+
+	case int64:
+		return BuildTree(json.Number(strconv.FormatInt(v, 10)), key, path)
+
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return BuildTree(json.Number("NaN"), key, path)
+		case math.IsInf(v, 1):
+			return BuildTree(json.Number("Infinity"), key, path)
+		case math.IsInf(v, -1):
+			return BuildTree(json.Number("-Infinity"), key, path)
+		}
+		return BuildTree(json.Number(strconv.FormatFloat(v, 'g', -1, 64)), key, path)
+	}
+
+	return BuildTree(data, key, path)
+}
+
+// keys returns the members of a table in source order. Keys the metadata
+// does not list, such as those of inline tables inside arrays, follow in
+// sorted order.
+func (c *tomlConverter) keys(table map[string]interface{}, keyPath []string) []string {
+	keys := make([]string, 0, len(table))
+	listed := make(map[string]bool, len(table))
+	for _, k := range c.order[strings.Join(keyPath, "\x00")] {
+		if _, ok := table[k]; ok && !listed[k] {
+			keys = append(keys, k)
+			listed[k] = true
+		}
+	}
+
+	var rest []string
+	for k := range table {
+		if !listed[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// formatTOMLTime formats a TOML date or time as written. Local dates and
+// times carry marker locations from the decoder so they keep their form.
+func formatTOMLTime(t time.Time) string {
+	switch t.Location().String() {
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
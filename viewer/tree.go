@@ -1,9 +1,92 @@
 package viewer
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
 
-// BuildTree creates a tree structure from JSON data
+// BuildTree creates a tree structure from JSON data. Object members are
+// sorted by key, since Go maps carry no order of their own. Other Go
+// values, such as structs, typed maps and slices, are shown as
+// encoding/json would marshal them, with struct fields in declaration
+// order; the values of their nodes are plain maps and slices.
 func BuildTree(data interface{}, key, path string) *Node {
+	return treeIndex(nil).build(data, key, path)
+}
+
+// treeIndex maps the objects and arrays of a source tree to their nodes by
+// identity. Values selected out of the source data (e.g. by JSONPath) are
+// the very same maps and slices, so the index lets them be rebuilt with
+// the source's member order.
+type treeIndex map[uintptr]*Node
+
+// indexTree indexes every object and non-empty array under root
+func indexTree(root *Node) treeIndex {
+	idx := make(treeIndex)
+	idx.add(root)
+	return idx
+}
+
+// add indexes the subtree under n
+func (idx treeIndex) add(n *Node) {
+	idx.put(n)
+	for _, child := range n.Children {
+		idx.add(child)
+	}
+}
+
+// put indexes n alone, e.g. after its value has grown
+func (idx treeIndex) put(n *Node) {
+	if id := valueID(n.Value); id != 0 {
+		idx[id] = n
+	}
+}
+
+// valueID returns the identity of a map or slice, or 0 for anything else
+func valueID(data interface{}) uintptr {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		return reflect.ValueOf(v).Pointer()
+	case []interface{}:
+		if len(v) > 0 {
+			return reflect.ValueOf(v).Pointer()
+		}
+	}
+	return 0
+}
+
+// lookup returns the source node holding data, if any
+func (idx treeIndex) lookup(data interface{}) *Node {
+	if idx == nil {
+		return nil
+	}
+	src := idx[valueID(data)]
+	if src == nil {
+		return nil
+	}
+	if v, ok := data.([]interface{}); ok && len(v) != len(src.Children) {
+		return nil
+	}
+	return src
+}
+
+// build creates the tree for data, reusing indexed source nodes as-is
+func (idx treeIndex) build(data interface{}, key, path string) *Node {
+	return newReflectBuilder(idx).value(data, key, path)
+}
+
+// value creates the tree for decoded JSON, handing other Go values to the
+// reflection builder. A map or slice that contains itself is cut short.
+func (b *reflectBuilder) value(data interface{}, key, path string) *Node {
+	if src := b.idx.lookup(data); src != nil {
+		node := src.Clone()
+		node.Key = key
+		node.rebase(path)
+		return node
+	}
+
 	node := &Node{
 		Key:  key,
 		Path: path,
@@ -11,25 +94,64 @@ func BuildTree(data interface{}, key, path string) *Node {
 
 	switch v := data.(type) {
 	case map[string]interface{}:
+		rv := reflect.ValueOf(v)
+		if !b.enter(node, rv) {
+			node.Type = NullNode
+			return node
+		}
+		defer b.leave(rv)
 		node.Type = ObjectNode
 		node.Value = v
-		for k, val := range v {
-			child := BuildTree(val, k, path+"."+k)
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var converted map[string]interface{}
+		for _, k := range keys {
+			child := b.value(v[k], k, path+"."+k)
 			child.Parent = node
 			node.Children = append(node.Children, child)
+			if converted == nil && !isPlainValue(child.Value, v[k]) {
+				converted = make(map[string]interface{}, len(v))
+			}
+		}
+		if converted != nil {
+			// Go values inside the map were converted, so the node holds
+			// a plain copy for JSONPath
+			for _, child := range node.Children {
+				converted[child.Key] = child.Value
+			}
+			node.Value = converted
 		}
 	case []interface{}:
+		rv := reflect.ValueOf(v)
+		if !b.enter(node, rv) {
+			node.Type = NullNode
+			return node
+		}
+		defer b.leave(rv)
 		node.Type = ArrayNode
 		node.Value = v
+		var converted []interface{}
 		for i, val := range v {
-			child := BuildTree(val, fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", path, i))
+			child := b.value(val, fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", path, i))
 			child.Parent = node
 			node.Children = append(node.Children, child)
+			if converted == nil && !isPlainValue(child.Value, val) {
+				converted = make([]interface{}, 0, len(v))
+			}
+		}
+		if converted != nil {
+			for _, child := range node.Children {
+				converted = append(converted, child.Value)
+			}
+			node.Value = converted
 		}
 	case string:
 		node.Type = StringNode
 		node.Value = v
-	case float64:
+	case json.Number, float64:
 		node.Type = NumberNode
 		node.Value = v
 	case bool:
@@ -38,11 +160,50 @@ func BuildTree(data interface{}, key, path string) *Node {
 	case nil:
 		node.Type = NullNode
 		node.Value = nil
+	default:
+		return b.build(reflect.ValueOf(data), key, path)
 	}
 
 	return node
 }
 
+// isPlainValue reports whether a node built from data kept data as its
+// value, rather than converting it from a Go value
+func isPlainValue(built, data interface{}) bool {
+	switch data.(type) {
+	case map[string]interface{}, []interface{}:
+		return valueID(built) == valueID(data)
+	case string, json.Number, float64, bool, nil:
+		return true
+	}
+	return false
+}
+
+// rebase rewrites the paths of the subtree so that it is rooted at path
+func (n *Node) rebase(path string) {
+	n.Path = path
+	for i, child := range n.Children {
+		if n.Type == ArrayNode {
+			child.rebase(fmt.Sprintf("%s[%d]", path, i))
+		} else {
+			child.rebase(path + "." + child.Key)
+		}
+	}
+}
+
+// Clone returns a deep copy of the subtree rooted at n, detached from its parent
+func (n *Node) Clone() *Node {
+	clone := *n
+	clone.Parent = nil
+	clone.Children = nil
+	for _, child := range n.Children {
+		c := child.Clone()
+		c.Parent = &clone
+		clone.Children = append(clone.Children, c)
+	}
+	return &clone
+}
+
 // CountNodes recursively counts the total number of nodes in the tree
 func CountNodes(node *Node) int {
 	count := 1
@@ -119,6 +280,19 @@ func (n *Node) FindPath(path string) *Node {
 	return nil
 }
 
+// findErrorNode returns the first node in the subtree that carries an error
+func findErrorNode(n *Node) *Node {
+	if n.Err != nil {
+		return n
+	}
+	for _, child := range n.Children {
+		if found := findErrorNode(child); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
 // GetParentChain returns the chain of parent nodes up to the root
 func (n *Node) GetParentChain() []*Node {
 	var chain []*Node
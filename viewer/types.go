@@ -1,6 +1,8 @@
 package viewer
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
@@ -18,6 +20,8 @@ const (
 	NumberNode
 	BoolNode
 	NullNode
+	DateTimeNode // a date or time from formats that have them, such as TOML
+	BytesNode    // a byte string from binary formats, with a Bytes value
 )
 
 // Node represents a node in the JSON tree
@@ -29,6 +33,10 @@ type Node struct {
 	Parent   *Node
 	Expanded bool
 	Path     string
+	Err      error  // set when the node could not be parsed cleanly
+	Comment  string // source comment preceding the node (JSON5/JSONC)
+	Tag      string // type annotation, such as a CBOR tag or MessagePack extension type
+	Raw      string // the string the value was decoded from, such as embedded JSON
 }
 
 // Config holds configuration options for the JSON viewer
@@ -44,6 +52,52 @@ type Config struct {
 	EnableMouse       bool
 	EnableClipboard   bool
 	
+	// Lenient loads the part of a malformed document that parsed instead
+	// of failing, marking the node where parsing stopped
+	Lenient bool
+	
+	// MultiDocument makes NewFromReader decode a stream of concatenated
+	// top-level values instead of a single document
+	MultiDocument bool
+	
+	// MarkInexactNumbers flags numbers that would change value if read
+	// as float64, such as 64-bit IDs beyond 2^53
+	MarkInexactNumbers bool
+	
+	// CSVDelimiter separates fields for NewFromCSV; zero means a comma
+	CSVDelimiter rune
+	
+	// InferTypes makes NewFromCSV read numbers and booleans as such and
+	// empty fields as null, instead of keeping every field a string
+	InferTypes bool
+	
+	// ExpandEmbeddedJSON shows strings that hold a JSON object or array
+	// as that value, marked as embedded JSON, so that they can be expanded
+	// and queried; copying one still gives the original string
+	ExpandEmbeddedJSON bool
+	
+	// DecodeBase64 shows JWTs as their decoded header, payload and
+	// signature, and base64 strings as the JSON, text or bytes they hold;
+	// copying one still gives the original string
+	DecodeBase64 bool
+	
+	// ShowTimestamps annotates epoch numbers, such as 1718000000 or
+	// 1718000000000, and RFC 3339 strings with their date and age
+	ShowTimestamps bool
+	
+	// TimeZone is where timestamp annotations are shown; nil means local
+	TimeZone *time.Location
+	
+	// Reload loads the source again for the reload key and Model.Reload,
+	// e.g. by re-reading a file
+	Reload func() (Model, error)
+	
+	// ChangeHighlight is how long values that changed in a reload stay
+	// highlighted; zero turns highlighting off. Nodes are compared by path,
+	// so array elements are matched by index: an element inserted or
+	// removed mid-array shows every element after it as changed.
+	ChangeHighlight time.Duration
+	
 	// Callbacks
 	OnSelect     func(*Node)
 	OnExpand     func(*Node)
@@ -74,6 +128,13 @@ type Theme struct {
 	Breadcrumb  lipgloss.Style
 	Match       lipgloss.Style
 	Border      lipgloss.Style
+	Error       lipgloss.Style
+	Comment     lipgloss.Style
+	Added       lipgloss.Style
+	Removed     lipgloss.Style
+	Changed     lipgloss.Style
+	DateTime    lipgloss.Style
+	Bytes       lipgloss.Style
 }
 
 // KeyMap defines the key bindings for the viewer
@@ -100,6 +161,9 @@ type KeyMap struct {
 	NextMatch    key.Binding
 	PrevMatch    key.Binding
 	Help         key.Binding
+	Reload       key.Binding
+	BytesFormat  key.Binding
+	SortByTime   key.Binding
 	Quit         key.Binding
 }
 
@@ -107,6 +171,8 @@ type KeyMap struct {
 type Model struct {
 	// Core data
 	root          *Node
+	source        *Node
+	index         treeIndex
 	rawData       interface{}
 	config        Config
 	
@@ -121,7 +187,13 @@ type Model struct {
 	// File info
 	filename      string
 	fileSize      int64
+	compression   string
+	compressedSize int64
 	nodeCount     int
+	lineCount     int
+	loadErr       error
+	tabs          []string
+	activeTab     int
 	
 	// Mode state
 	filter        string
@@ -129,7 +201,9 @@ type Model struct {
 	jsonpathMode  bool
 	searchMode    bool
 	gotoMode      bool
+	jsonpathQuery bool // the applied filter is a JSONPath query, not text
 	showHelp      bool
+	bytesBase64   bool
 	
 	// Search state
 	searchMatches []*Node
@@ -137,6 +211,16 @@ type Model struct {
 	
 	// Position restoration
 	savedNodePath string
+
+	// Status message
+	status        string
+	statusErr     bool
+	statusSticky  bool
+	
+	// Changes from the last reload, by path
+	changes       map[string]changeKind
+	removed       []*Node
+	changeGen     int
 	
 	// Embedded mode
 	embedded      bool
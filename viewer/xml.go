@@ -0,0 +1,172 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NewFromXML creates a new JSON viewer from an XML document. Elements
+// become objects keyed by tag name, attributes become "@name" members,
+// repeated elements become arrays and text content becomes a "#text"
+// member, or the element's value when it has no attributes or children.
+// Comments are shown as annotations on the element that follows them.
+func NewFromXML(data []byte, config ...Config) (Model, error) {
+	root, err := parseXML(data)
+	return newFromParsed(root, err, config...)
+}
+
+// xmlElement is an element being read, with its text gathered so far
+type xmlElement struct {
+	name string
+	node *Node
+	text []string
+}
+
+// parseXML builds a tree with the document's root element as the only
+// member of the root object. A syntax error stops parsing, returning the
+// elements read so far alongside the error.
+func parseXML(data []byte) (*Node, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = true
+
+	root := &Node{Type: ObjectNode, Path: "$"}
+	stack := []*xmlElement{{node: root}}
+	var comment string
+
+	for {
+		// RawToken keeps namespace prefixes such as soap:Envelope as
+		// written; tag matching is checked here instead
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			if len(stack) > 1 {
+				err = io.ErrUnexpectedEOF
+			} else {
+				break
+			}
+		}
+		if err != nil {
+			return finishXML(stack, xmlParseError(data, dec, err))
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := xmlName(t.Name)
+			node := &Node{Key: name, Type: ObjectNode, Comment: comment}
+			comment = ""
+			for _, attr := range t.Attr {
+				addXMLChild(node, &Node{Key: "@" + xmlName(attr.Name), Type: StringNode, Value: attr.Value})
+			}
+			stack = append(stack, &xmlElement{name: name, node: node})
+
+		case xml.EndElement:
+			top := stack[len(stack)-1]
+			if len(stack) == 1 || top.name != xmlName(t.Name) {
+				msg := fmt.Errorf("element <%s> closed by </%s>", top.name, xmlName(t.Name))
+				if len(stack) == 1 {
+					msg = fmt.Errorf("unexpected end element </%s>", xmlName(t.Name))
+				}
+				return finishXML(stack, xmlParseError(data, dec, msg))
+			}
+			stack = closeXMLElement(stack)
+
+		case xml.CharData:
+			top := stack[len(stack)-1]
+			if text := strings.TrimSpace(string(t)); text != "" && len(stack) > 1 {
+				top.text = append(top.text, text)
+			}
+
+		case xml.Comment:
+			comment = joinComments(comment, strings.TrimSpace(string(t)))
+		}
+	}
+
+	return finishXML(stack, nil)
+}
+
+// closeXMLElement completes the element on top of the stack and adds it to
+// its parent. An element with only text becomes a string, and one with
+// nothing at all becomes null.
+func closeXMLElement(stack []*xmlElement) []*xmlElement {
+	top := stack[len(stack)-1]
+	node := top.node
+	text := strings.Join(top.text, " ")
+
+	switch {
+	case len(node.Children) == 0 && text != "":
+		node.Type = StringNode
+		node.Value = text
+	case len(node.Children) == 0:
+		node.Type = NullNode
+	case text != "":
+		addXMLChild(node, &Node{Key: "#text", Type: StringNode, Value: text})
+	}
+
+	stack = stack[:len(stack)-1]
+	addXMLChild(stack[len(stack)-1].node, node)
+	return stack
+}
+
+// addXMLChild adds a member to an element. A repeated element name turns
+// the existing member into an array holding every occurrence.
+func addXMLChild(parent, child *Node) {
+	for i, existing := range parent.Children {
+		if existing.Key != child.Key {
+			continue
+		}
+		if existing.Type != ArrayNode {
+			group := &Node{Key: existing.Key, Type: ArrayNode, Parent: parent}
+			existing.Key = "[0]"
+			existing.Parent = group
+			group.Children = []*Node{existing}
+			parent.Children[i] = group
+			existing = group
+		}
+		child.Key = fmt.Sprintf("[%d]", len(existing.Children))
+		child.Parent = existing
+		existing.Children = append(existing.Children, child)
+		return
+	}
+
+	child.Parent = parent
+	parent.Children = append(parent.Children, child)
+}
+
+// finishXML closes any elements left open by an error, marking the
+// innermost one, then fills in values and paths
+func finishXML(stack []*xmlElement, err error) (*Node, error) {
+	if err != nil {
+		stack[len(stack)-1].node.Err = err
+	}
+	for len(stack) > 1 {
+		stack = closeXMLElement(stack)
+	}
+
+	root := stack[0].node
+	if err != nil && len(root.Children) == 0 {
+		return nil, err
+	}
+	finishTree(root)
+	root.rebase("$")
+	return root, err
+}
+
+// xmlName renders a raw name with its namespace prefix, if any
+func xmlName(name xml.Name) string {
+	if name.Space != "" {
+		return name.Space + ":" + name.Local
+	}
+	return name.Local
+}
+
+// xmlParseError places an XML error at the decoder's position
+func xmlParseError(data []byte, dec *xml.Decoder, err error) *ParseError {
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		err = errors.New(syntaxErr.Msg)
+	}
+	return newParseErrorAt(data, dec.InputOffset(), err)
+}
@@ -0,0 +1,346 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewFromYAML creates a new JSON viewer from YAML data. Mappings become
+// objects in source order, anchors and aliases are resolved, and comments
+// are shown as annotations. A stream of several documents is shown as
+// sibling documents $[0], $[1], ...
+func NewFromYAML(data []byte, config ...Config) (Model, error) {
+	root, err := parseYAML(data)
+	return newFromParsed(root, err, config...)
+}
+
+// parseYAML decodes every document in data. A single document becomes the
+// root; several are gathered under a synthetic array root. When a later
+// document fails to parse, the documents before it are still returned.
+func parseYAML(data []byte) (*Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []*yaml.Node
+	var err error
+	for {
+		var doc yaml.Node
+		if err = dec.Decode(&doc); err != nil {
+			break
+		}
+		docs = append(docs, &doc)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if err != nil {
+		err = newYAMLParseError(data, err)
+	}
+
+	c := newYAMLConverter(data)
+	if len(docs) == 1 && err == nil {
+		node := c.convert(docs[0], "", "$")
+		if c.err != nil {
+			return nil, c.err
+		}
+		return node, nil
+	}
+	if len(docs) == 0 {
+		if err != nil {
+			return nil, err
+		}
+		return BuildTree(nil, "", "$"), nil
+	}
+
+	root := &Node{Type: ArrayNode, Path: "$"}
+	values := make([]interface{}, 0, len(docs))
+	for i, doc := range docs {
+		child := c.convert(doc, fmt.Sprintf("[%d]", i), fmt.Sprintf("$[%d]", i))
+		if c.err != nil {
+			err = c.err
+			break
+		}
+		child.Parent = root
+		root.Children = append(root.Children, child)
+		values = append(values, child.Value)
+	}
+	root.Value = values
+	root.Err = err
+	return root, err
+}
+
+// yamlErrorLine finds the line number in a yaml.v3 error message
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// newYAMLParseError places a YAML error on the line it names. yaml.v3
+// reports no column, and for syntax errors the line is often where the
+// enclosing collection starts, so the column is left unknown.
+func newYAMLParseError(data []byte, err error) *ParseError {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		err = errors.New(typeErr.Errors[0])
+	}
+
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return newParseErrorAt(data, int64(len(data)), err)
+	}
+	line, _ := strconv.Atoi(match[1])
+	offset := yamlOffset(data, line, 1)
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	msg = strings.TrimPrefix(msg, match[0]+": ")
+	parseErr := newParseErrorAt(data, offset, errors.New(msg))
+	parseErr.Column = 0
+	return parseErr
+}
+
+// errExcessiveAliasing is reported for documents whose aliases expand to
+// far more nodes than they hold, such as the "billion laughs" attack
+var errExcessiveAliasing = errors.New("document contains excessive aliasing")
+
+// yamlOffset finds the byte offset of a 1-based line and column, counted
+// in characters, as yaml.v3 reports positions
+func yamlOffset(data []byte, line, column int) int64 {
+	offset := 0
+	for i := 1; i < line && offset < len(data); i++ {
+		next := bytes.IndexByte(data[offset:], '\n')
+		if next < 0 {
+			return int64(len(data))
+		}
+		offset += next + 1
+	}
+	for i := 1; i < column && offset < len(data) && data[offset] != '\n'; i++ {
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return int64(offset)
+}
+
+// yamlConverter builds Node trees from yaml.v3 nodes, resolving aliases
+type yamlConverter struct {
+	data []byte
+
+	// resolving holds the anchors being expanded, to cut recursive aliases
+	resolving map[*yaml.Node]bool
+
+	// nodeCount counts the nodes converted and aliasCount those converted
+	// while expanding an alias, which yaml.v3 limits the same way when
+	// decoding into Go values
+	nodeCount  int
+	aliasCount int
+	err        *ParseError
+}
+
+func newYAMLConverter(data []byte) *yamlConverter {
+	return &yamlConverter{data: data, resolving: make(map[*yaml.Node]bool)}
+}
+
+// allowedAliasRatio is the share of converted nodes that may come from
+// alias expansion, which shrinks as documents grow. It matches yaml.v3.
+func allowedAliasRatio(nodeCount int) float64 {
+	const low, high = 400000, 4000000
+	switch {
+	case nodeCount <= low:
+		return 0.99
+	case nodeCount >= high:
+		return 0.10
+	}
+	return 0.99 - 0.89*float64(nodeCount-low)/float64(high-low)
+}
+
+// convert builds the subtree for a YAML node. Once aliases have expanded
+// too far, conversion stops and c.err is set.
+func (c *yamlConverter) convert(n *yaml.Node, key, path string) *Node {
+	if c.err != nil {
+		return BuildTree(nil, key, path)
+	}
+	c.nodeCount++
+	if len(c.resolving) > 0 {
+		c.aliasCount++
+	}
+	if c.aliasCount > 100 && c.nodeCount > 1000 &&
+		float64(c.aliasCount)/float64(c.nodeCount) > allowedAliasRatio(c.nodeCount) {
+		c.err = newParseErrorAt(c.data, yamlOffset(c.data, n.Line, n.Column), errExcessiveAliasing)
+		return BuildTree(nil, key, path)
+	}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return BuildTree(nil, key, path)
+		}
+		node := c.convert(n.Content[0], key, path)
+		node.Comment = joinComments(yamlComment(n), node.Comment)
+		return node
+
+	case yaml.AliasNode:
+		if c.resolving[n.Alias] {
+			// A recursive alias cannot be expanded; show the reference
+			return BuildTree("*"+n.Value, key, path)
+		}
+		c.resolving[n.Alias] = true
+		node := c.convert(n.Alias, key, path)
+		delete(c.resolving, n.Alias)
+		return node
+
+	case yaml.MappingNode:
+		node := &Node{Key: key, Path: path, Type: ObjectNode}
+		value := make(map[string]interface{})
+		node.Value = value
+		c.convertMapping(node, value, n)
+		node.Comment = yamlComment(n)
+		return node
+
+	case yaml.SequenceNode:
+		node := &Node{Key: key, Path: path, Type: ArrayNode}
+		values := make([]interface{}, 0, len(n.Content))
+		for i, item := range n.Content {
+			child := c.convert(item, fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", path, i))
+			child.Parent = node
+			node.Children = append(node.Children, child)
+			values = append(values, child.Value)
+		}
+		node.Value = values
+		node.Comment = yamlComment(n)
+		return node
+	}
+
+	node := BuildTree(yamlScalar(n), key, path)
+	node.Comment = yamlComment(n)
+	return node
+}
+
+// convertMapping adds the members of a mapping to node. Merge keys (<<)
+// copy in the members of the mappings they reference, without overriding
+// keys the mapping sets itself.
+func (c *yamlConverter) convertMapping(node *Node, value map[string]interface{}, n *yaml.Node) {
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind == yaml.ScalarNode && k.Tag == "!!merge" {
+			merges = append(merges, v)
+			continue
+		}
+
+		name := yamlKey(k)
+		child := c.convert(v, name, node.Path+"."+name)
+		child.Parent = node
+		child.Comment = joinComments(yamlComment(k), child.Comment)
+		c.setMember(node, value, child)
+	}
+
+	for _, merge := range merges {
+		sources := []*yaml.Node{merge}
+		if merge.Kind == yaml.SequenceNode {
+			sources = merge.Content
+		}
+		for _, src := range sources {
+			merged := c.convert(src, "", node.Path)
+			for _, child := range merged.Children {
+				if _, exists := value[child.Key]; exists {
+					continue
+				}
+				child.Parent = node
+				c.setMember(node, value, child)
+			}
+		}
+	}
+}
+
+// setMember adds or replaces an object member, later duplicates winning
+func (c *yamlConverter) setMember(node *Node, value map[string]interface{}, child *Node) {
+	if _, dup := value[child.Key]; dup {
+		for i, existing := range node.Children {
+			if existing.Key == child.Key {
+				node.Children[i] = child
+			}
+		}
+	} else {
+		node.Children = append(node.Children, child)
+	}
+	value[child.Key] = child.Value
+}
+
+// yamlKey renders a mapping key as an object key. Non-scalar keys, which
+// JSON cannot express, are shown in YAML flow style.
+func yamlKey(k *yaml.Node) string {
+	if k.Kind == yaml.ScalarNode {
+		return k.Value
+	}
+	if k.Kind == yaml.AliasNode && k.Alias != nil {
+		return yamlKey(k.Alias)
+	}
+	out, err := yaml.Marshal(k)
+	if err != nil {
+		return k.Value
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// yamlScalar returns the value of a scalar by its resolved tag. Numbers are
+// kept as json.Number so that large integers stay exact.
+func yamlScalar(n *yaml.Node) interface{} {
+	switch n.ShortTag() {
+	case "!!null":
+		return nil
+	case "!!bool":
+		var b bool
+		if n.Decode(&b) == nil {
+			return b
+		}
+	case "!!int":
+		var i int64
+		if n.Decode(&i) == nil {
+			return json.Number(strconv.FormatInt(i, 10))
+		}
+		var u uint64
+		if n.Decode(&u) == nil {
+			return json.Number(strconv.FormatUint(u, 10))
+		}
+	case "!!float":
+		var f float64
+		if n.Decode(&f) != nil {
+			break
+		}
+		switch {
+		case math.IsNaN(f):
+			return json.Number("NaN")
+		case math.IsInf(f, 1):
+			return json.Number("Infinity")
+		case math.IsInf(f, -1):
+			return json.Number("-Infinity")
+		case json.Valid([]byte(n.Value)):
+			return json.Number(n.Value)
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return n.Value
+}
+
+// yamlComment returns the comments attached to a YAML node, without the
+// leading '#'
+func yamlComment(n *yaml.Node) string {
+	var parts []string
+	for _, comment := range []string{n.HeadComment, n.LineComment, n.FootComment} {
+		for _, line := range strings.Split(comment, "\n") {
+			line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+			if line != "" {
+				parts = append(parts, line)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// joinComments joins two comments with a space, skipping empty ones
+func joinComments(a, b string) string {
+	return strings.TrimSpace(a + " " + b)
+}
@@ -0,0 +1,62 @@
+package viewer
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseYAMLRejectsExcessiveAliasing(t *testing.T) {
+	doc := "a: &a [x, x, x, x, x, x, x, x, x, x]\n"
+	prev := "a"
+	for _, name := range []string{"b", "c", "d", "e", "f", "g", "h", "i"} {
+		doc += name + ": &" + name + " [" + strings.TrimSuffix(strings.Repeat("*"+prev+", ", 10), ", ") + "]\n"
+		prev = name
+	}
+
+	_, err := parseYAML([]byte(doc))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, errExcessiveAliasing) {
+		t.Fatalf("parseYAML error = %v, want excessive aliasing ParseError", err)
+	}
+	if parseErr.Line < 2 {
+		t.Errorf("error line = %d, want an aliasing line", parseErr.Line)
+	}
+}
+
+func TestParseYAMLExpandsModestAliases(t *testing.T) {
+	doc := "base: &base {name: x, size: 1}\nitems: [*base, *base, *base]\nmerged:\n  <<: *base\n  size: 2\n"
+	root, err := parseYAML([]byte(doc))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+	items := root.Value.(map[string]interface{})["items"].([]interface{})
+	if len(items) != 3 {
+		t.Fatalf("items = %v, want 3 expanded aliases", items)
+	}
+	merged := root.Value.(map[string]interface{})["merged"].(map[string]interface{})
+	if merged["name"] != "x" || merged["size"] != json.Number("2") {
+		t.Errorf("merged = %v", merged)
+	}
+}
+
+func TestParseYAMLErrorHasNoColumn(t *testing.T) {
+	_, err := parseYAML([]byte("a: 1\nb: @x\n"))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("parseYAML error = %v, want ParseError", err)
+	}
+	if parseErr.Line != 2 || parseErr.Column != 0 {
+		t.Errorf("position = line %d, column %d, want line 2 and no column", parseErr.Line, parseErr.Column)
+	}
+	if got := parseErr.Error(); !strings.HasPrefix(got, "line 2: ") {
+		t.Errorf("Error() = %q, want it to name only the line", got)
+	}
+	if snippet := parseErr.Snippet(1); strings.Contains(snippet, "^") {
+		t.Errorf("Snippet has a caret:\n%s", snippet)
+	}
+	if hint := parseErr.Hint(); hint != "" {
+		t.Errorf("Hint() = %q, want none", hint)
+	}
+}